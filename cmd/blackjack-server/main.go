@@ -0,0 +1,92 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/server"
+)
+
+func main() {
+	addr := flag.String("addr", ":7021", "TCP address to host the table on")
+	rulesName := flag.String("rules", "classic", "table rules preset (classic, vegas-strip, atlantic-city, downtown, european)")
+	soft17 := flag.String("soft17", "", "override the preset's soft 17 rule: \"hit\" (H17) or \"stand\" (S17)")
+	seed := flag.Int64("seed", 0, "shuffle the shoe from this seed (0 picks one at random)")
+	timeout := flag.Duration("timeout", time.Minute, "time each player has for a decision (0 waits forever)")
+	httpAddr := flag.String("http", "", "also serve the HTTP/JSON session API and the table's WebSocket feed on this address, such as :8021")
+	flag.Parse()
+
+	rules, err := game.RulesByName(*rulesName)
+	if err != nil {
+		fail(err)
+	}
+	switch *soft17 {
+	case "":
+	case "hit", "h17":
+		rules.DealerHitsSoft17 = true
+	case "stand", "s17":
+		rules.DealerHitsSoft17 = false
+	default:
+		fail(fmt.Errorf("invalid -soft17 value %q (use \"hit\" or \"stand\")", *soft17))
+	}
+	var seedErr error
+	if *seed == 0 {
+		*seed, seedErr = game.NewSeed()
+	}
+
+	l, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fail(err)
+	}
+
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	srv := server.NewTableServer(rules, *seed)
+	srv.Timeout = *timeout
+	srv.Log = logger
+	if seedErr != nil {
+		logger.Printf("warning: %v", seedErr)
+	}
+
+	// Close the table cleanly on Ctrl-C
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	go func() {
+		<-interrupt
+		logger.Println("shutting down")
+		srv.Close()
+	}()
+
+	if *httpAddr != "" {
+		api := server.NewAPI(rules)
+		api.IdleTimeout = time.Hour
+		api.Log = logger
+		mux := http.NewServeMux()
+		mux.Handle("/", api)
+		mux.Handle("GET /table/live", srv)
+		go func() {
+			logger.Printf("HTTP API listening on %s; watch the table at ws://%s/table/live", *httpAddr, *httpAddr)
+			if err := http.ListenAndServe(*httpAddr, mux); err != nil {
+				fail(err)
+			}
+		}()
+	}
+
+	logger.Printf("Table: %s (%s)", rules.Name, rules.Summary())
+	logger.Printf("Seed: %d", *seed)
+	logger.Printf("Listening on %s; join with: blackjack connect %s", l.Addr(), l.Addr())
+	if err := srv.Serve(l); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(2)
+}
@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/server"
+)
+
+// runConnect implements the connect subcommand, a thin client for blackjack-server
+// The server runs the game; the client only shows the table and answers its prompts.
+func runConnect(args []string) {
+	flags := flag.NewFlagSet("connect", flag.ExitOnError)
+	name := flags.String("name", os.Getenv("USER"), "name to sit down with")
+	watch := flags.Bool("watch", false, "watch the table without taking a seat")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: blackjack connect [flags] [host:port]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	addr := "localhost:7021"
+	if flags.NArg() > 0 {
+		addr = flags.Arg(0)
+	}
+	if *name == "" && !*watch {
+		*name = "Player"
+	}
+
+	in := game.NewInputSource(os.Stdin, os.Stdout)
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		in.Println(err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(conn)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	r := &remoteTable{conn: conn, in: in}
+	for line := range lines {
+		verb, arg, _ := strings.Cut(line, " ")
+		switch verb {
+		case "HELLO":
+			in.Printf("🔌 Connected to %s\n", addr)
+			if *watch {
+				in.Println("👀 Watching the table. Press Ctrl-C to stop.")
+			} else {
+				r.send("JOIN " + *name)
+			}
+		case "WAIT":
+			in.Println("⏳ You'll be seated when the next round starts.")
+		case "WELCOME":
+			fields := strings.Fields(arg)
+			if len(fields) == 2 {
+				r.seat, _ = strconv.Atoi(fields[0])
+				in.Printf("🪑 Seated in seat %d with %s chips. Enter 'q' at the bet prompt to leave.\n", r.seat, fields[1])
+			}
+		case "STATE":
+			r.state(arg)
+		case "BET":
+			r.bet(arg)
+		case "INSURANCE":
+			r.insurance(arg)
+		case "ACT":
+			r.act(arg)
+		case "RESULT":
+			r.result(arg)
+		case "LEFT":
+			in.Printf("👋 You left the table (%s).\n", arg)
+			r.send("QUIT")
+		case "ERROR":
+			in.Println("⚠️  " + arg)
+		case "BYE":
+			return
+		}
+	}
+	in.Println("The server closed the connection.")
+}
+
+// remoteTable is the client's view of a table hosted by blackjack-server
+type remoteTable struct {
+	conn     net.Conn
+	in       *game.InputSource
+	seat     int
+	snapshot server.TableSnapshot
+}
+
+func (r *remoteTable) send(line string) {
+	fmt.Fprintln(r.conn, line)
+}
+
+// state shows the table whenever a hand is about to be played or insured
+func (r *remoteTable) state(data string) {
+	var snap server.TableSnapshot
+	if err := json.Unmarshal([]byte(data), &snap); err != nil {
+		r.in.Println("⚠️  bad snapshot from server:", err)
+		return
+	}
+	r.snapshot = snap
+	switch r.snapshot.Prompt {
+	case "act", "insurance":
+		r.in.Println()
+		r.in.Println(renderSnapshot(r.snapshot))
+	case "bet":
+		if r.snapshot.Turn != r.seat {
+			r.in.Printf("⏳ Waiting for seat %d to bet...\n", r.snapshot.Turn)
+		}
+	}
+}
+
+func (r *remoteTable) bet(arg string) {
+	var min, max int
+	fmt.Sscanf(arg, "%d %d", &min, &max)
+	r.in.Printf("\n🎰 Round %d\n", r.snapshot.Round+1)
+	for {
+		bet, err := r.in.PromptBet(min, max)
+		if err == game.ErrToggleCount {
+			r.in.Println("The count isn't available at a networked table.")
+			continue
+		}
+		if err != nil {
+			r.send("LEAVE")
+			return
+		}
+		r.send(fmt.Sprintf("BET %d", bet))
+		return
+	}
+}
+
+func (r *remoteTable) insurance(arg string) {
+	max, _ := strconv.Atoi(arg)
+	take, err := r.in.PromptYesNo("Take insurance?")
+	bet := 0
+	if err == nil && take {
+		bet, err = r.in.PromptInsurance(max)
+		if err != nil {
+			bet = 0
+		}
+	}
+	r.send(fmt.Sprintf("INSURANCE %d", bet))
+}
+
+func (r *remoteTable) act(arg string) {
+	handArg, list, _ := strings.Cut(arg, " ")
+	hand, _ := strconv.Atoi(handArg)
+	var available []game.Action
+	for _, name := range strings.Split(list, ",") {
+		if action, err := game.ParseAction(name); err == nil {
+			available = append(available, action)
+		}
+	}
+
+	total := 0
+	for _, seat := range r.snapshot.Seats {
+		if seat.Seat == r.seat {
+			total = len(seat.Hands)
+		}
+	}
+	action, err := r.in.PromptAction(available, hand, total, nil)
+	if err != nil {
+		action = game.ActionStand
+	}
+	r.send("ACT " + strings.ToLower(action.String()))
+}
+
+// result shows how each seat did in the round
+func (r *remoteTable) result(data string) {
+	var result server.RoundResult
+	if err := json.Unmarshal([]byte(data), &result); err != nil {
+		r.in.Println("⚠️  bad result from server:", err)
+		return
+	}
+
+	r.in.Printf("\nRound %d: dealer has %s (%d)\n", result.Round, cardList(result.Dealer.Cards), result.Dealer.Value)
+	for _, seat := range result.Seats {
+		marker := " "
+		if seat.Seat == r.seat {
+			marker = ">"
+		}
+		outcomes := make([]string, 0, len(seat.Hands))
+		for _, hand := range seat.Hands {
+			outcomes = append(outcomes, fmt.Sprintf("%s %s (%d)", hand.Outcome, cardList(hand.Cards), hand.Value))
+		}
+		r.in.Printf("%s Seat %d, %s: %s; %+d chips, bank %d\n",
+			marker, seat.Seat, seat.Name, strings.Join(outcomes, ", "), seat.Net, seat.Bank)
+	}
+}
+
+// renderSnapshot renders a table snapshot, marking the seat whose turn it is
+func renderSnapshot(snap server.TableSnapshot) string {
+	var sb strings.Builder
+	sb.WriteString("+------------------------------------------+\n")
+
+	dealer := "| Dealer: "
+	if snap.Dealer.HoleHidden {
+		dealer += "[??, " + strings.Trim(cardList(snap.Dealer.Cards), "[]") + "]"
+	} else {
+		dealer += fmt.Sprintf("%s (%d)", cardList(snap.Dealer.Cards), snap.Dealer.Value)
+	}
+	sb.WriteString(boxRow(dealer))
+
+	for _, seat := range snap.Seats {
+		for i, hand := range seat.Hands {
+			marker := " "
+			if seat.Seat == snap.Turn && i+1 == snap.Active {
+				marker = ">"
+			}
+			label := fmt.Sprintf("|%s%d %s: ", marker, seat.Seat, seat.Name)
+			if len(seat.Hands) > 1 {
+				label = fmt.Sprintf("|%s%d %s (Hand %d/%d): ", marker, seat.Seat, seat.Name, i+1, len(seat.Hands))
+			}
+			row := label + cardList(hand.Cards)
+			if hand.Bust {
+				row += " (BUST)"
+			} else {
+				row += fmt.Sprintf(" (%d)", hand.Value)
+			}
+			if hand.Surrendered {
+				row += " [SURRENDERED]"
+			}
+			sb.WriteString(boxRow(row))
+		}
+	}
+
+	sb.WriteString("+------------------------------------------+")
+	return sb.String()
+}
+
+// boxRow pads a row of the table to the column width and closes it
+func boxRow(row string) string {
+	// Pad to the width of the border, counting runes since the suits take several bytes
+	if n := utf8.RuneCountInString(row); n < 43 {
+		row += strings.Repeat(" ", 43-n)
+	}
+	return row + "|\n"
+}
+
+// cardList formats cards as "[K♠, 7♥]"
+func cardList(cards []game.Card) string {
+	names := make([]string, len(cards))
+	for i, card := range cards {
+		names[i] = card.String()
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
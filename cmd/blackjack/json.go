@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/history"
+	"github.com/DanDo385/blackjack-cli/internal/server"
+)
+
+// jsonMessage is one line written to stdout in -json mode
+type jsonMessage struct {
+	Type         string             `json:"type"`             // "hello", "prompt", "result", "error" or "end"
+	Prompt       string             `json:"prompt,omitempty"` // Decision awaited: "bet", "insurance" or "action"
+	Phase        game.Phase         `json:"phase"`
+	Bank         int                `json:"bank"`
+	Round        int                `json:"round"` // Rounds dealt so far
+	Rules        *game.RuleSet      `json:"rules,omitempty"`
+	Seed         int64              `json:"seed,omitempty"`
+	MinBet       int                `json:"min_bet,omitempty"`
+	MaxBet       int                `json:"max_bet,omitempty"`
+	Spots        int                `json:"spots,omitempty"` // Bets expected at a bet prompt
+	Dealer       *server.DealerView `json:"dealer,omitempty"`
+	Hands        []server.HandView  `json:"hands,omitempty"`
+	Hand         int                `json:"hand,omitempty"`    // Hand the decision is for, from 1
+	Actions      []game.Action      `json:"actions,omitempty"` // Legal actions at an action prompt
+	InsuranceMax int                `json:"insurance_max,omitempty"`
+	Net          int                `json:"net,omitempty"` // Chips won or lost in the round
+	Results      []game.HandResult  `json:"results,omitempty"`
+	Reason       string             `json:"reason,omitempty"` // Why the session ended: "quit" or "busted"
+	Error        string             `json:"error,omitempty"`
+}
+
+// jsonDecision is one line read from stdin in -json mode
+type jsonDecision struct {
+	Bet       *int         `json:"bet"`
+	Bets      []int        `json:"bets"`
+	Insurance *int         `json:"insurance"`
+	Action    *game.Action `json:"action"`
+	Quit      bool         `json:"quit"`
+}
+
+// jsonPlayer plays for a program on the other end of stdin and stdout
+// Each prompt is written as one JSON object on a line of its own and the decision is read
+// back as one; a line that can't be used is answered with an error and the prompt again.
+type jsonPlayer struct {
+	in    *bufio.Reader
+	out   *json.Encoder
+	spots int
+}
+
+// runJSON plays a session in -json mode, for bots driving the engine as a subprocess
+// Nothing but JSON Lines is written to stdout; problems outside the game go to stderr.
+func runJSON(rules game.RuleSet, seedFlag string, spots int, historyPath string) {
+	seed, err := newSeed(seedFlag, func(format string, args ...any) {
+		fmt.Fprintf(os.Stderr, format, args...)
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	g := game.NewGameWithSeed(rules, seed)
+
+	var hist *history.Writer
+	if historyPath != "" {
+		hist, err = history.Open(historyPath, history.NewSessionID(g.Seed))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Hand history disabled: %v\n", err)
+		} else {
+			defer hist.Close()
+		}
+	}
+
+	out := json.NewEncoder(os.Stdout)
+	out.SetEscapeHTML(false)
+	p := &jsonPlayer{in: bufio.NewReader(os.Stdin), out: out, spots: spots}
+	p.send(jsonMessage{Type: "hello", Phase: g.CurrentPhase, Bank: g.Bank, Rules: &g.Rules, Seed: g.Seed, Spots: spots})
+
+	reason := "quit"
+	for {
+		err := g.PlayRound(p)
+		if errors.Is(err, game.ErrQuit) {
+			break
+		}
+		if err != nil {
+			p.send(jsonMessage{Type: "error", Phase: g.CurrentPhase, Bank: g.Bank, Round: g.RoundsPlayed, Error: err.Error()})
+			break
+		}
+
+		if err := g.CheckBank(); err != nil {
+			fmt.Fprintf(os.Stderr, "Bank integrity check failed: %v\n", err)
+		}
+		if hist != nil {
+			if err := hist.Write(g.LastRound()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing hand history: %v\n", err)
+			}
+		}
+
+		rec := g.LastRound()
+		dealer := server.NewDealerView(g.DealerHand, false)
+		p.send(jsonMessage{
+			Type:    "result",
+			Phase:   g.CurrentPhase,
+			Bank:    g.Bank,
+			Round:   g.RoundsPlayed,
+			Dealer:  &dealer,
+			Net:     rec.BankAfter - rec.BankBefore,
+			Results: rec.Hands,
+		})
+		if g.CurrentPhase == game.PhaseGameOver {
+			reason = "busted"
+			break
+		}
+	}
+	p.send(jsonMessage{Type: "end", Phase: g.CurrentPhase, Bank: g.Bank, Round: g.RoundsPlayed, Reason: reason})
+}
+
+func (p *jsonPlayer) send(msg jsonMessage) {
+	if err := p.out.Encode(msg); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing to stdout: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// ask sends a prompt and reads the decision
+// Prompts during a round carry the visible table, leaving out the hole card until the
+// dealer plays. A line that isn't JSON is reported and the prompt sent again; when stdin
+// is closed the program has gone away and ErrQuit is returned.
+func (p *jsonPlayer) ask(g *game.Game, msg jsonMessage) (jsonDecision, error) {
+	msg.Type = "prompt"
+	msg.Phase = g.CurrentPhase
+	msg.Bank = g.Bank
+	msg.Round = g.RoundsPlayed
+	if g.CurrentPhase != game.PhaseBetting {
+		dealer := server.NewDealerView(g.DealerHand, !g.HoleCardRevealed)
+		msg.Dealer = &dealer
+		msg.Hands = server.NewHandViews(g.PlayerHands)
+	}
+
+	for {
+		p.send(msg)
+		line, err := p.readLine()
+		if err != nil {
+			return jsonDecision{}, game.ErrQuit
+		}
+
+		var dec jsonDecision
+		if err := json.Unmarshal([]byte(line), &dec); err != nil {
+			p.reject(g, fmt.Errorf("invalid decision: %v", err))
+			continue
+		}
+		return dec, nil
+	}
+}
+
+// readLine returns the next line from stdin that isn't blank
+func (p *jsonPlayer) readLine() (string, error) {
+	for {
+		line, err := p.in.ReadString('\n')
+		if strings.TrimSpace(line) != "" {
+			return line, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
+
+// reject reports a decision that can't be used; the prompt is then sent again
+func (p *jsonPlayer) reject(g *game.Game, err error) {
+	p.send(jsonMessage{Type: "error", Phase: g.CurrentPhase, Bank: g.Bank, Round: g.RoundsPlayed, Error: err.Error()})
+}
+
+func (p *jsonPlayer) Bet(g *game.Game) (int, error) {
+	bets, err := p.askBets(g, 1)
+	if err != nil {
+		return 0, err
+	}
+	return bets[0], nil
+}
+
+func (p *jsonPlayer) Bets(g *game.Game) ([]int, error) {
+	return p.askBets(g, p.spots)
+}
+
+// askBets asks for up to spots bets; {"bet": n} bets n on every spot the bank can cover
+func (p *jsonPlayer) askBets(g *game.Game, spots int) ([]int, error) {
+	msg := jsonMessage{Prompt: "bet", MinBet: g.Rules.MinBet, MaxBet: g.MaxBet(), Spots: spots}
+	for {
+		dec, err := p.ask(g, msg)
+		if err != nil {
+			return nil, err
+		}
+		if dec.Quit {
+			return nil, game.ErrQuit
+		}
+
+		bets := dec.Bets
+		if dec.Bet != nil {
+			bet := *dec.Bet
+			for i := 0; i < spots && (i+1)*bet <= g.Bank; i++ {
+				bets = append(bets, bet)
+			}
+			if len(bets) == 0 {
+				bets = []int{bet}
+			}
+		}
+		if err := checkJSONBets(g, bets, spots); err != nil {
+			p.reject(g, err)
+			continue
+		}
+		return bets, nil
+	}
+}
+
+// checkJSONBets checks bets before the round is dealt, so a bad one can be asked for again
+func checkJSONBets(g *game.Game, bets []int, spots int) error {
+	if len(bets) == 0 {
+		return fmt.Errorf(`expected {"bet": chips}, {"bets": [chips, ...]} or {"quit": true}`)
+	}
+	if len(bets) > spots {
+		return fmt.Errorf("expected at most %d bets", spots)
+	}
+	total := 0
+	for _, bet := range bets {
+		if bet < g.Rules.MinBet || bet > g.MaxBet() {
+			return fmt.Errorf("bet must be %d-%d", g.Rules.MinBet, g.MaxBet())
+		}
+		total += bet
+	}
+	if total > g.Bank {
+		return fmt.Errorf("bets total %d but the bank is %d", total, g.Bank)
+	}
+	return nil
+}
+
+func (p *jsonPlayer) Insurance(g *game.Game, max int) (int, error) {
+	msg := jsonMessage{Prompt: "insurance", Hand: g.ActiveHandIndex + 1, InsuranceMax: max}
+	for {
+		dec, err := p.ask(g, msg)
+		if err != nil {
+			return 0, err
+		}
+		if dec.Quit {
+			return 0, game.ErrQuit
+		}
+		if dec.Insurance == nil || *dec.Insurance < 0 || *dec.Insurance > max {
+			p.reject(g, fmt.Errorf(`expected {"insurance": chips} with 0-%d chips, or {"quit": true}`, max))
+			continue
+		}
+		return *dec.Insurance, nil
+	}
+}
+
+func (p *jsonPlayer) Act(g *game.Game, hand *game.Hand, available []game.Action) (game.Action, error) {
+	msg := jsonMessage{Prompt: "action", Hand: g.ActiveHandIndex + 1, Actions: available}
+	for {
+		dec, err := p.ask(g, msg)
+		if err != nil {
+			return game.ActionStand, err
+		}
+		if dec.Quit {
+			return game.ActionStand, game.ErrQuit
+		}
+		if dec.Action == nil || !game.ContainsAction(available, *dec.Action) {
+			names := make([]string, len(available))
+			for i, action := range available {
+				names[i] = strings.ToLower(action.String())
+			}
+			p.reject(g, fmt.Errorf(`expected {"action": name} with one of %s, or {"quit": true}`, strings.Join(names, ", ")))
+			continue
+		}
+		return *dec.Action, nil
+	}
+}
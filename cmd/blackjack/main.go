@@ -1,281 +1,407 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strconv"
 
 	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/history"
+	"github.com/DanDo385/blackjack-cli/internal/save"
+	"github.com/DanDo385/blackjack-cli/internal/strategy"
 )
 
 func main() {
-	fmt.Println("╔════════════════════════════════════════╗")
-	fmt.Println("║         BLACKJACK CLI GAME             ║")
-	fmt.Println("╚════════════════════════════════════════╝")
-	fmt.Println()
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "replay":
+			runReplay(os.Args[2:])
+			return
+		case "connect":
+			runConnect(os.Args[2:])
+			return
+		}
+	}
+
+	rulesName := flag.String("rules", "classic", "table rules preset (classic, vegas-strip, atlantic-city, downtown, european)")
+	soft17 := flag.String("soft17", "", "override the preset's soft 17 rule: \"hit\" (H17) or \"stand\" (S17)")
+	trainerMode := flag.Bool("trainer", false, "grade every decision against basic strategy")
+	showEV := flag.Bool("ev", false, "add the exact expected value of every play to the hint, and show what a worse play cost")
+	countName := flag.String("count", "", "track the count with a system: hilo, ko, hiopt2, omega2 or zen")
+	showCount := flag.Bool("show-count", false, "show the count from the start (toggle with 'c' at the bet prompt)")
+	seedFlag := flag.String("seed", "", "shuffle the shoe from this seed to replay a session")
+	historyPath := flag.String("history", dataPath("history.jsonl"), "append every round to this JSON Lines hand-history file (\"\" to disable)")
+	savePath := flag.String("save", dataPath("save.json"), "save the session to this file after every decision and offer to resume it (\"\" to disable)")
+	botName := flag.String("bot", "", "watch a bot play instead: basic, random or count")
+	spots := flag.Int("spots", 1, "play this many spots a round from your bank (1-7)")
+	seats := flag.String("seats", "", "play hot-seat at a table of up to 7 seats, e.g. \"Ann,Bob,basic\" (bot names seat bots)")
+	jsonMode := flag.Bool("json", false, "speak JSON Lines on stdin and stdout, for programs playing the game")
+	shoeFile := flag.String("shoe-file", "", "deal the cards listed in this file in order, e.g. \"AS KD 8H 8C\", to practice set-up hands")
+	shoeEnd := flag.String("shoe-end", "stop", "when the -shoe-file cards run out: \"stop\" the session or \"shuffle\" a full shoe and play on")
+	flag.Parse()
+
+	// Every prompt in the session reads from the one source, so piped answers aren't lost,
+	// and everything the session prints goes to its output
+	in := game.NewInputSource(os.Stdin, os.Stdout)
+
+	rules, err := game.RulesByName(*rulesName)
+	if err != nil {
+		in.Println(err)
+		os.Exit(2)
+	}
+
+	switch *soft17 {
+	case "":
+	case "hit", "h17":
+		rules.DealerHitsSoft17 = true
+	case "stand", "s17":
+		rules.DealerHitsSoft17 = false
+	default:
+		in.Printf("invalid -soft17 value %q (use \"hit\" or \"stand\")\n", *soft17)
+		os.Exit(2)
+	}
 
-	g := game.NewGame()
+	if *spots < 1 || *spots > game.MaxSeats {
+		in.Printf("invalid -spots value %d: must be 1-%d\n", *spots, game.MaxSeats)
+		os.Exit(2)
+	}
 
-	for g.Bank > 0 {
-		// Betting phase
-		fmt.Printf("\n🎰 Current Bank: %d chips\n", g.Bank)
-		bet, err := game.PromptBet(os.Stdin, g.Bank)
+	var stacked []game.Card
+	if *shoeFile != "" {
+		if *seats != "" || *jsonMode {
+			in.Println("-shoe-file can't be used with -seats or -json")
+			os.Exit(2)
+		}
+		if *shoeEnd != "stop" && *shoeEnd != "shuffle" {
+			in.Printf("invalid -shoe-end value %q (use \"stop\" or \"shuffle\")\n", *shoeEnd)
+			os.Exit(2)
+		}
+		stacked, err = loadShoeFile(*shoeFile)
 		if err != nil {
-			fmt.Printf("Error reading bet: %v\n", err)
-			continue
+			in.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	// A program playing the game gets nothing on stdout but JSON, and no save to resume
+	if *jsonMode {
+		if *seats != "" || *botName != "" {
+			fmt.Fprintln(os.Stderr, "-json can't be used with -seats or -bot")
+			os.Exit(2)
+		}
+		runJSON(rules, *seedFlag, *spots, *historyPath)
+		return
+	}
+
+	in.Println("╔════════════════════════════════════════╗")
+	in.Println("║         BLACKJACK CLI GAME             ║")
+	in.Println("╚════════════════════════════════════════╝")
+	in.Println()
+
+	if *seats != "" {
+		runTable(in, rules, *seats, *seedFlag, *countName, *showCount, *trainerMode, *showEV, *historyPath)
+		return
+	}
+
+	runSession(in, sessionOptions{
+		rules:       rules,
+		seed:        *seedFlag,
+		countName:   *countName,
+		showCount:   *showCount,
+		trainer:     *trainerMode,
+		showEV:      *showEV,
+		historyPath: *historyPath,
+		savePath:    *savePath,
+		botName:     *botName,
+		spots:       *spots,
+		stacked:     stacked,
+		shoeFile:    *shoeFile,
+		shoeEnd:     *shoeEnd,
+	})
+}
+
+// sessionOptions are the flags of a session at the table on your own
+type sessionOptions struct {
+	rules       game.RuleSet
+	seed        string // -seed, or "" for a new random seed
+	countName   string
+	showCount   bool
+	trainer     bool
+	showEV      bool
+	historyPath string
+	savePath    string
+	botName     string
+	spots       int
+	stacked     []game.Card // Cards from the -shoe-file, or nil
+	shoeFile    string
+	shoeEnd     string
+}
+
+// runSession plays a session on your own against the dealer, or watches a bot play one
+// Answers are read from in and everything the session shows is written to its output.
+func runSession(in *game.InputSource, opts sessionOptions) {
+	rules := opts.rules
+	stacked := opts.stacked
+	savePath := opts.savePath
+
+	// Neither a bot's session nor a stacked shoe is saved, so they can't overwrite yours
+	if opts.botName != "" || stacked != nil {
+		savePath = ""
+	}
+
+	// Offer to resume the last session unless a seed asks for a specific new one
+	var g *game.Game
+	var trainer *strategy.Trainer
+	var session string
+	countVisible := opts.showCount
+	if savePath != "" && opts.seed == "" {
+		saved, err := save.Read(savePath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			in.Printf("Could not load the saved session: %v\n", err)
+		} else if err == nil && promptResume(in, saved) {
+			g = saved.Game
+			trainer = saved.Trainer
+			session = saved.Session
+			countVisible = saved.CountVisible
 		}
+	}
 
-		// Start hand (bet is validated but not yet deducted)
-		err = g.StartHand(bet)
+	if g == nil {
+		seed, err := newSeed(opts.seed, in.Printf)
 		if err != nil {
-			fmt.Printf("Error starting hand: %v\n", err)
-			continue
+			in.Println(err)
+			os.Exit(2)
 		}
+		g = game.NewGameWithSeed(rules, seed)
+		session = history.NewSessionID(g.Seed)
+	}
+	if stacked != nil {
+		stackShoe(g, stacked)
+	}
 
-		// Show initial state
-		fmt.Println()
-		fmt.Println(game.RenderState(g, true))
-		fmt.Println()
-
-		// Deduct the bet after showing the initial state
-		// This allows the doubling check to see the full bank balance
-		g.Bank -= bet
-
-		// Insurance phase
-		if g.CurrentPhase == game.PhaseInsurance {
-			dealerCard := g.DealerHand.Cards[1]
-			maxInsurance := bet / 2
-			if maxInsurance > g.Bank {
-				maxInsurance = g.Bank
-			}
-
-			if maxInsurance == 0 {
-				err = g.DeclineInsurance()
-				if err != nil {
-					fmt.Printf("Error declining insurance: %v\n", err)
-					continue
-				}
-			} else {
-				prompt := fmt.Sprintf("Dealer shows %s. Take insurance?", dealerCard.String())
-				takeInsurance, err := game.PromptYesNo(os.Stdin, prompt)
-				if err != nil {
-					fmt.Printf("Error reading input: %v\n", err)
-					continue
-				}
-
-				if takeInsurance {
-					insuranceBet, err := game.PromptInsurance(os.Stdin, maxInsurance)
-					if err != nil {
-						fmt.Printf("Error reading insurance bet: %v\n", err)
-						continue
-					}
-
-					if insuranceBet > 0 {
-						g.Bank -= insuranceBet
-						err = g.TakeInsurance(insuranceBet)
-						if err != nil {
-							fmt.Printf("Error taking insurance: %v\n", err)
-							g.Bank += insuranceBet // Refund
-							continue
-						}
-					} else {
-						err = g.DeclineInsurance()
-						if err != nil {
-							fmt.Printf("Error declining insurance: %v\n", err)
-							continue
-						}
-					}
-				} else {
-					err = g.DeclineInsurance()
-					if err != nil {
-						fmt.Printf("Error declining insurance: %v\n", err)
-						continue
-					}
-				}
-			}
+	in.Printf("Table: %s (%s)\n", g.Rules.Name, g.Rules.Summary())
+	in.Println(g.Rules.Soft17Rule())
+	in.Println(game.RenderSeed(g))
+	if stacked != nil {
+		in.Printf("🃏 Dealing %d cards from %s, then %s\n", len(stacked), opts.shoeFile, shoeEndDescription(opts.shoeEnd))
+	}
+
+	var hist *history.Writer
+	if opts.historyPath != "" {
+		var err error
+		hist, err = history.Open(opts.historyPath, session)
+		if err != nil {
+			in.Printf("Hand history disabled: %v\n", err)
+		} else {
+			defer hist.Close()
+			in.Printf("📝 Hand history: %s\n", opts.historyPath)
 		}
+	}
 
-		// Check if dealer has blackjack after insurance
-		if g.CurrentPhase == game.PhaseResolution {
-			// Resolve payouts (insurance + main hand)
-			g.ResolvePayouts()
+	if opts.countName != "" {
+		system, err := game.ParseCountSystem(opts.countName)
+		if err != nil {
+			in.Println(err)
+			os.Exit(2)
+		}
+		if g.Counter == nil || g.Counter.System != system {
+			g.EnableCounting(system)
+		}
+	}
+	if g.Counter != nil {
+		in.Printf("🧮 Counting with %s. Enter 'c' at the bet prompt to show or hide the count.\n", g.Counter.System)
+	}
 
-			fmt.Println("\n🃏 Dealer has Blackjack!")
-			fmt.Println(game.RenderResult(g))
+	if opts.trainer && trainer == nil {
+		trainer = strategy.NewTrainer()
+	}
+	if trainer != nil {
+		in.Println("🎓 Trainer mode: every decision is graded against basic strategy.")
+	}
 
-			// Continue to next hand
-			if !promptContinue() {
-				break
-			}
-			continue
+	// Either you play, or you watch a bot play
+	var player game.Player
+	you := &human{in: in, trainer: trainer, countVisible: countVisible, showEV: opts.showEV, spots: opts.spots}
+	if opts.botName != "" {
+		bot, err := strategy.NewBot(opts.botName, g, 10)
+		if err != nil {
+			in.Println(err)
+			os.Exit(2)
 		}
+		player = watched{Player: bot, name: opts.botName, in: in}
+		in.Printf("🤖 The %s bot is playing. You'll be asked after each round whether to carry on.\n", opts.botName)
+	} else {
+		player = you
+	}
 
-		// Check for player blackjack and skip to dealer
-		if g.PlayerHands[0].IsBlackjack() {
-			fmt.Println("\n🃏 Blackjack!")
-			// Skip player action and go straight to dealer
-			for g.CurrentPhase == game.PhasePlayerAction {
-				g.PlayerAction(game.ActionStand)
-			}
+	// saveSession saves the session so it can be resumed after quitting
+	saveSession := func() {
+		if savePath == "" {
+			return
+		}
+		err := save.Write(savePath, &save.File{
+			Session:      session,
+			Game:         g,
+			Trainer:      trainer,
+			CountVisible: you.countVisible,
+		})
+		if err != nil {
+			in.Printf("Error saving session: %v\n", err)
 		}
+	}
+	you.beforePrompt = saveSession
+
+	g.Subscribe(func(e game.Event) { announce(in, e) })
 
-		// Player action phase - play each hand independently
-		for g.CurrentPhase == game.PhasePlayerAction {
-			currentHand := g.GetCurrentHand()
-			if currentHand == nil {
-				break
-			}
-
-			// Capture the current hand index at the start of this iteration
-			currentHandIndex := g.ActiveHandIndex
-			handNum := currentHandIndex + 1
-			totalHands := len(g.PlayerHands)
-
-			// Display current hand info
-			fmt.Println()
-			fmt.Println(game.RenderCurrentHand(g))
-			fmt.Println()
-
-			// Handle split aces - they only get one card and then move on
-			if currentHand.IsSplitAces {
-				// Split aces already have their one card dealt during split
-				// Just show the result and advance
-				fmt.Println("Split aces receive only one card.")
-				fmt.Println()
-				fmt.Println(game.RenderState(g, true))
-				fmt.Println()
-
-				// Advance to next hand
-				err := g.PlayerAction(game.ActionStand)
-				if err != nil {
-					fmt.Printf("Error: %v\n", err)
-					break
-				}
-				continue
-			}
-
-			// Play this hand until it's done (stand, bust, double, or surrender)
-			// Continue as long as we're still on the same hand index
-			for g.CurrentPhase == game.PhasePlayerAction && g.ActiveHandIndex == currentHandIndex {
-				// Refresh current hand reference
-				currentHand = g.GetCurrentHand()
-				if currentHand == nil {
-					break
-				}
-
-				// Refresh total hands count in case we split
-				totalHands = len(g.PlayerHands)
-
-				// Check if hand is bust
-				if currentHand.IsBust() {
-					fmt.Println("💥 BUST!")
-					fmt.Println()
-					fmt.Println(game.RenderState(g, true))
-					fmt.Println()
-
-					err := g.PlayerAction(game.ActionStand)
-					if err != nil {
-						fmt.Printf("Error: %v\n", err)
-						break
-					}
-					break
-				}
-
-				// Check if hand is 21 (auto-stand)
-				if currentHand.Value() == 21 {
-					fmt.Println()
-					fmt.Println(game.RenderState(g, true))
-					fmt.Println()
-
-					err := g.PlayerAction(game.ActionStand)
-					if err != nil {
-						fmt.Printf("Error: %v\n", err)
-						break
-					}
-					break
-				}
-
-				// Get available actions
-				actions := g.GetAvailableActions()
-				if len(actions) == 0 {
-					break
-				}
-
-				// Show board state
-				fmt.Println(game.RenderState(g, true))
-				fmt.Println()
-
-				// Prompt for action
-				action, err := game.PromptAction(os.Stdin, actions, handNum, totalHands)
-				if err != nil {
-					fmt.Printf("Error reading action: %v\n", err)
-					continue
-				}
-
-				// Perform action
-				err = g.PlayerAction(action)
-				if err != nil {
-					fmt.Printf("Error performing action: %v\n", err)
-					continue
-				}
-
-				// Show board after action
-				fmt.Println()
-				fmt.Println(game.RenderState(g, true))
-				fmt.Println()
-
-				// Show result of action
-				currentHand = g.GetCurrentHand()
-				if currentHand != nil {
-					if action == game.ActionHit && currentHand.IsBust() {
-						fmt.Println("💥 BUST!")
-					} else if action == game.ActionDouble {
-						// Double ends the hand, show result
-						if currentHand.IsBust() {
-							fmt.Println("💥 BUST!")
-						}
-					} else if action == game.ActionSurrender {
-						fmt.Println("Hand surrendered.")
-					}
-				}
-
-				// If action was stand, double, or surrender, the hand is done
-				// (advanceToNextHand was called, so we break out of inner loop)
-				if action == game.ActionStand || action == game.ActionDouble || action == game.ActionSurrender {
-					break
-				}
-
-				// If we split, we continue playing the current hand (first split hand)
-				// Refresh the display for the next iteration
-				if action == game.ActionSplit {
-					fmt.Println()
-					fmt.Println(game.RenderCurrentHand(g))
-					fmt.Println()
-				}
-			}
+	if g.CurrentPhase != game.PhaseBetting && g.CurrentPhase != game.PhaseGameOver {
+		in.Println("\n▶️  Resuming the round in progress")
+		in.Println()
+		in.Println(game.RenderState(g, !g.HoleCardRevealed))
+	}
+
+	for g.CurrentPhase != game.PhaseGameOver {
+		if stacked != nil && opts.shoeEnd == "stop" && shoeFileSpent(g) {
+			in.Printf("\n🃏 Every card from %s has been dealt.\n", opts.shoeFile)
+			break
+		}
+
+		err := g.PlayRound(player)
+		if errors.Is(err, game.ErrQuit) {
+			break
+		}
+		if err != nil {
+			in.Printf("\nError: %v\n", err)
+			break
 		}
 
 		// Show final result
-		fmt.Println(game.RenderResult(g))
+		if g.DealerHasBlackjack {
+			in.Println("\n🃏 Dealer has Blackjack!")
+		} else if g.PlayerHands[0].IsBlackjack() {
+			in.Println("\n🃏 Blackjack!")
+		}
+		in.Println(game.RenderResult(g))
+		checkBank(in, g)
+		writeHistory(in, hist, g)
+		saveSession()
 
 		// Check if game is over
-		if g.Bank <= 0 {
-			fmt.Println("\n💸 You're busted. Thanks for playing!")
+		if g.CurrentPhase == game.PhaseGameOver {
+			in.Println("\n💸 You're busted. Thanks for playing!")
 			break
 		}
 
 		// Continue prompt
-		if !promptContinue() {
+		if !promptContinue(in) {
 			break
 		}
 	}
 
+	if trainer != nil {
+		in.Println()
+		in.Printf("%s", trainer.Report())
+	}
+
+	// A busted session can't be resumed
+	if g.CurrentPhase == game.PhaseGameOver && savePath != "" {
+		if err := save.Remove(savePath); err != nil {
+			in.Printf("Error removing save file: %v\n", err)
+		}
+	}
+
 	// Final bank
-	fmt.Printf("\n🏦 Final Bank: %d chips\n", g.Bank)
-	fmt.Println("\nThanks for playing!")
+	in.Printf("\n🏦 Final Bank: %d chips\n", g.Bank)
+	in.Println(game.RenderSeed(g))
+	in.Println("\nThanks for playing!")
+}
+
+// announce prints the events the table should be told about as they happen
+func announce(in *game.InputSource, e game.Event) {
+	switch e := e.(type) {
+	case game.ShoeShuffled:
+		if e.Refilled {
+			in.Println("\n🔀 The stacked cards have run out. Shuffling a fresh shoe...")
+		} else if e.Emergency {
+			in.Println("\n🔀 The shoe ran out. Shuffling the discards...")
+		} else {
+			in.Println("\n🔀 The cut card is out. Shuffling the shoe...")
+		}
+	case game.ActionTaken:
+		if e.Bust {
+			in.Println("💥 BUST!")
+		} else if e.Action == game.ActionSurrender {
+			in.Println("Hand surrendered.")
+		}
+	}
+}
+
+// checkBank warns if the bank no longer matches the round's chip ledger
+func checkBank(in *game.InputSource, g *game.Game) {
+	if err := g.CheckBank(); err != nil {
+		in.Printf("⚠️  Bank integrity check failed: %v\n", err)
+	}
+}
+
+// writeHistory appends the round just resolved to the hand history, if enabled
+func writeHistory(in *game.InputSource, hist *history.Writer, g *game.Game) {
+	if hist == nil || g.LastRound() == nil {
+		return
+	}
+	if err := hist.Write(g.LastRound()); err != nil {
+		in.Printf("Error writing hand history: %v\n", err)
+	}
+}
+
+// newSeed parses the -seed flag, or draws a new seed when it is empty
+// A seed that had to be taken from the clock is reported through warn.
+func newSeed(seedFlag string, warn func(format string, args ...any)) (int64, error) {
+	if seedFlag == "" {
+		seed, err := game.NewSeed()
+		if err != nil {
+			warn("⚠️  %v\n", err)
+		}
+		return seed, nil
+	}
+	seed, err := strconv.ParseInt(seedFlag, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid -seed value %q: must be an integer", seedFlag)
+	}
+	return seed, nil
+}
+
+// dataPath returns the path of a file in the ~/.blackjack data directory
+func dataPath(name string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return name
+	}
+	return filepath.Join(home, ".blackjack", name)
+}
+
+// promptResume describes a saved session and asks whether to resume it
+func promptResume(in *game.InputSource, saved *save.File) bool {
+	g := saved.Game
+	state := "between rounds"
+	if g.CurrentPhase != game.PhaseBetting {
+		state = fmt.Sprintf("round %d in progress", g.RoundsPlayed)
+	}
+	in.Printf("💾 Saved session from %s: %s, %d chips, %d rounds played, %s\n",
+		saved.SavedAt.Local().Format("Jan 2 15:04"), g.Rules.Name, g.Bank, g.RoundsPlayed, state)
+
+	resume, err := in.PromptYesNo("Resume your last session?")
+	if err != nil {
+		return false
+	}
+	in.Println()
+	return resume
 }
 
-func promptContinue() bool {
-	cont, err := game.PromptYesNo(os.Stdin, "\nPlay another hand?")
+func promptContinue(in *game.InputSource) bool {
+	cont, err := in.PromptYesNo("\nPlay another hand?")
 	if err != nil {
 		return false
 	}
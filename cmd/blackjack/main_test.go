@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// TestPipedSession plays a round from piped answers, as in the README's golden-file example
+// Everything the session shows must reach the input source's output and nothing stdout.
+func TestPipedSession(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	var out bytes.Buffer
+	// Bet 10, double the 11 against the dealer's 2, then leave
+	in := game.NewInputSource(strings.NewReader("10\nd\nn\n"), &out)
+	runSession(in, sessionOptions{rules: game.DefaultRules(), seed: "42", spots: 1})
+
+	os.Stdout = stdout
+	w.Close()
+	leaked, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leaked) > 0 {
+		t.Errorf("the session wrote to stdout instead of the input source:\n%s", leaked)
+	}
+
+	transcript := out.String()
+	for _, want := range []string{
+		"🎲 Session seed: 42",
+		"Enter bet (1-1000, q to leave): ",
+		"| You: [4♣, 7♥] (11)",
+		"| You: [4♣, 7♥, 10♦] (21)",
+		"Win! Pays 20 chips",
+		"Play another hand? (y/n): ",
+		"🏦 Final Bank: 1020 chips",
+	} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("transcript is missing %q:\n%s", want, transcript)
+		}
+	}
+}
+
+// TestJSONQuit checks that {"quit": true} leaves at every kind of prompt
+func TestJSONQuit(t *testing.T) {
+	g := game.NewGameWithSeed(game.DefaultRules(), game.FixedSeed)
+	if err := g.StartHand(10); err != nil {
+		t.Fatal(err)
+	}
+	prompts := map[string]func(p *jsonPlayer) error{
+		"bet": func(p *jsonPlayer) error {
+			_, err := p.Bet(g)
+			return err
+		},
+		"insurance": func(p *jsonPlayer) error {
+			_, err := p.Insurance(g, 5)
+			return err
+		},
+		"action": func(p *jsonPlayer) error {
+			_, err := p.Act(g, g.PlayerHands[0], []game.Action{game.ActionHit, game.ActionStand})
+			return err
+		},
+	}
+	for name, prompt := range prompts {
+		t.Run(name, func(t *testing.T) {
+			var out bytes.Buffer
+			p := &jsonPlayer{in: bufio.NewReader(strings.NewReader("{\"quit\": true}\n")), out: json.NewEncoder(&out), spots: 1}
+			if err := prompt(p); !errors.Is(err, game.ErrQuit) {
+				t.Errorf("got %v, expected ErrQuit", err)
+			}
+			if strings.Contains(out.String(), `"error"`) {
+				t.Errorf("the quit was rejected:\n%s", out.String())
+			}
+		})
+	}
+}
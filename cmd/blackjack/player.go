@@ -0,0 +1,252 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/DanDo385/blackjack-cli/internal/analysis"
+	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/strategy"
+)
+
+// human is the Player at the keyboard
+// It shows the table before every decision, offers basic-strategy hints and, in
+// trainer mode, grades each decision as it is made.
+type human struct {
+	in           *game.InputSource
+	trainer      *strategy.Trainer
+	countVisible bool
+	// showEV adds the exact expected value of every play to the hint, and the cost of a worse play
+	showEV bool
+	// beforePrompt runs before every prompt, so quitting at a prompt loses nothing
+	beforePrompt func()
+	// At a multi-seat table, the seat being played and the table to show
+	seat  *game.Seat
+	table *game.Table
+	// spots is the number of spots to bet on each round
+	spots int
+}
+
+// show renders the table as the player sees it, with the dealer's hole card hidden
+func (h *human) show(g *game.Game) string {
+	if h.table != nil {
+		return game.RenderTable(h.table, true, h.seat)
+	}
+	return game.RenderState(g, true)
+}
+
+func (h *human) Bet(g *game.Game) (int, error) {
+	return h.bet(g, 0, g.MaxBet())
+}
+
+// Bets asks for a bet on each spot in turn, playing fewer spots if the bank runs short
+func (h *human) Bets(g *game.Game) ([]int, error) {
+	if h.spots <= 1 {
+		bet, err := h.Bet(g)
+		if err != nil {
+			return nil, err
+		}
+		return []int{bet}, nil
+	}
+
+	var bets []int
+	available := g.Bank
+	for spot := 1; spot <= h.spots; spot++ {
+		max := available
+		if g.Rules.MaxBet > 0 && g.Rules.MaxBet < max {
+			max = g.Rules.MaxBet
+		}
+		if max < g.Rules.MinBet {
+			break
+		}
+		bet, err := h.bet(g, spot, max)
+		if err != nil {
+			return nil, err
+		}
+		bets = append(bets, bet)
+		available -= bet
+	}
+	return bets, nil
+}
+
+// bet asks for one bet of up to max chips, for the given spot when playing several
+func (h *human) bet(g *game.Game, spot int, max int) (int, error) {
+	for {
+		h.beforePrompt()
+		if spot <= 1 {
+			if h.seat != nil {
+				h.in.Printf("\n🪑 %s's turn", h.seat.Name)
+			}
+			h.in.Printf("\n🎰 Current Bank: %d chips\n", g.Bank)
+			h.in.Println(game.RenderShoe(g))
+			if h.countVisible && g.Counter != nil {
+				h.in.Println(game.RenderCount(g))
+			}
+		}
+		if spot > 0 {
+			h.in.Printf("Spot %d: ", spot)
+		}
+
+		bet, err := h.in.PromptBet(g.Rules.MinBet, max)
+		if errors.Is(err, game.ErrToggleCount) {
+			if g.Counter == nil {
+				h.in.Println("Card counting is off. Start with -count hilo to enable it.")
+			} else {
+				h.countVisible = !h.countVisible
+			}
+			continue
+		}
+		return bet, err
+	}
+}
+
+func (h *human) Insurance(g *game.Game, max int) (int, error) {
+	h.beforePrompt()
+	h.in.Println()
+	h.in.Println(h.show(g))
+	h.in.Println()
+
+	dealerCard := g.DealerUpcard()
+	prompt := fmt.Sprintf("Dealer shows %s. Take insurance?", dealerCard.String())
+	if g.Spots() > 1 {
+		prompt = fmt.Sprintf("Spot %d: %s", g.PlayerHands[g.ActiveHandIndex].Spot+1, prompt)
+	}
+	if h.seat != nil {
+		prompt = h.seat.Name + ": " + prompt
+	}
+	takeInsurance, err := h.in.PromptYesNo(prompt)
+	if err != nil {
+		return 0, err
+	}
+
+	bet := 0
+	if takeInsurance {
+		bet, err = h.in.PromptInsurance(max)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if h.trainer != nil {
+		h.in.Println(h.trainer.GradeInsurance(dealerCard, bet > 0))
+	}
+	return bet, nil
+}
+
+func (h *human) Act(g *game.Game, hand *game.Hand, available []game.Action) (game.Action, error) {
+	h.in.Println()
+	if h.seat != nil {
+		h.in.Printf("🪑 %s to play\n", h.seat.Name)
+	}
+	h.in.Println(game.RenderCurrentHand(g))
+	h.in.Println()
+
+	// Split aces receive only one card (unless they can be resplit under the table rules)
+	if hand.IsSplitAces && len(available) == 1 {
+		h.in.Println("Split aces receive only one card.")
+		h.in.Println()
+		h.in.Println(h.show(g))
+		return game.ActionStand, nil
+	}
+
+	h.beforePrompt()
+	h.in.Println(h.show(g))
+	h.in.Println()
+
+	// Prompt for action, offering the basic-strategy play as a hint
+	var ev *analysis.Analysis
+	var evErr error
+	analyze := func() {
+		if ev == nil && evErr == nil {
+			var a analysis.Analysis
+			a, evErr = analysis.Analyze(hand, g.DealerUpcard(), h.seenCards(g, hand), g.Rules, available)
+			ev = &a
+		}
+	}
+	hint := func() string {
+		advice := strategy.Recommend(hand, g.DealerUpcard(), g.Rules, available).String()
+		if !h.showEV {
+			return advice
+		}
+		analyze()
+		if evErr != nil {
+			return fmt.Sprintf("%s\n📐 EV unavailable: %v", advice, evErr)
+		}
+		return fmt.Sprintf("%s\n📐 EV: %s", advice, ev)
+	}
+	action, err := h.in.PromptAction(available, g.ActiveHandIndex+1, len(g.PlayerHands), hint)
+	if err != nil {
+		return 0, err
+	}
+
+	// Grade the decision before the hand changes
+	if h.trainer != nil {
+		h.in.Println(h.trainer.GradeAction(hand, g.DealerUpcard(), g.Rules, available, action))
+	}
+	if h.showEV {
+		analyze()
+		if evErr == nil {
+			if cost, ok := ev.Cost(action); ok && cost > 0 {
+				h.in.Printf("📐 %s gives up %.3f of a bet against %s\n",
+					action, cost, strings.ToLower(ev.Best().Action.String()))
+			}
+		}
+	}
+	return action, nil
+}
+
+// seenCards returns the cards known to be out of the shoe besides the hand and the upcard:
+// the discard tray and every other hand on the table
+func (h *human) seenCards(g *game.Game, hand *game.Hand) []game.Card {
+	seen := append([]game.Card(nil), g.Shoe.Discards...)
+	games := []*game.Game{g}
+	if h.table != nil {
+		games = nil
+		for _, seat := range h.table.Seats {
+			games = append(games, seat.Game)
+		}
+	}
+	for _, other := range games {
+		for _, h := range other.PlayerHands {
+			if h != hand {
+				seen = append(seen, h.Cards...)
+			}
+		}
+	}
+	return seen
+}
+
+// watched wraps a bot so each of its decisions is shown as it is made
+type watched struct {
+	game.Player
+	name string
+	in   *game.InputSource
+}
+
+func (w watched) Bet(g *game.Game) (int, error) {
+	bet, err := w.Player.Bet(g)
+	if err == nil {
+		w.in.Printf("\n🤖 %s bets %d chips (bank %d)\n", w.name, bet, g.Bank)
+	}
+	return bet, err
+}
+
+func (w watched) Insurance(g *game.Game, max int) (int, error) {
+	bet, err := w.Player.Insurance(g, max)
+	if err == nil {
+		if bet > 0 {
+			w.in.Printf("🤖 %s takes insurance for %d chips\n", w.name, bet)
+		} else {
+			w.in.Printf("🤖 %s declines insurance\n", w.name)
+		}
+	}
+	return bet, err
+}
+
+func (w watched) Act(g *game.Game, hand *game.Hand, available []game.Action) (game.Action, error) {
+	action, err := w.Player.Act(g, hand, available)
+	if err == nil {
+		w.in.Printf("🤖 %s: %s on %s vs %s\n", w.name, action, hand, g.DealerUpcard())
+	}
+	return action, err
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/history"
+)
+
+// runReplay implements the replay subcommand, which steps through recorded rounds
+func runReplay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	path := flags.String("history", dataPath("history.jsonl"), "hand-history file to replay")
+	session := flags.String("session", "", "session to replay (default: the most recent one)")
+	round := flags.Int("round", 0, "round to start from (default: the first)")
+	auto := flags.Bool("auto", false, "play the whole session back without waiting for input")
+	delay := flags.Duration("delay", time.Second, "pause between steps when auto-playing")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: blackjack replay [flags] [history file]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+	if flags.NArg() > 0 {
+		*path = flags.Arg(0)
+	}
+	in := game.NewInputSource(os.Stdin, os.Stdout)
+
+	entries, err := history.Read(*path)
+	if err != nil {
+		in.Println(err)
+		os.Exit(1)
+	}
+	sessions := history.Sessions(entries)
+	if len(sessions) == 0 {
+		in.Printf("%s has no recorded rounds\n", *path)
+		os.Exit(1)
+	}
+	if *session == "" {
+		*session = sessions[len(sessions)-1]
+	}
+	rounds := history.Session(entries, *session)
+	if len(rounds) == 0 {
+		in.Printf("session %q not found; recorded sessions:\n", *session)
+		for _, s := range sessions {
+			in.Println("  " + s)
+		}
+		os.Exit(1)
+	}
+
+	v := &viewer{in: in, rounds: rounds}
+	if *round != 0 {
+		if err := v.jump(*round); err != nil {
+			in.Println(err)
+			os.Exit(1)
+		}
+	} else if err := v.load(0); err != nil {
+		in.Println(err)
+		os.Exit(1)
+	}
+
+	in.Printf("Replaying session %s (%d rounds, seed %d)\n", *session, len(rounds), rounds[0].Seed)
+	in.Printf("Table: %s (%s)\n", rounds[0].Rules.Name, rounds[0].Rules.Summary())
+	v.show()
+
+	if *auto {
+		v.autoPlay(*delay)
+		return
+	}
+
+	for {
+		in.Printf("\n[Enter/n] next  [p] previous  [r N] jump to round N  [a] auto-play  [q] quit: ")
+		line, err := in.ReadLine()
+		if err != nil {
+			in.Println()
+			return
+		}
+		input := strings.Fields(strings.ToLower(line))
+		command := "n"
+		if len(input) > 0 {
+			command = input[0]
+		}
+
+		switch command {
+		case "n", "next":
+			if !v.next() {
+				in.Println("End of session.")
+				continue
+			}
+		case "p", "prev", "previous":
+			if !v.prev() {
+				in.Println("Start of session.")
+				continue
+			}
+		case "r", "round", "j", "jump":
+			if len(input) < 2 {
+				in.Println("Enter a round number, e.g. r 12")
+				continue
+			}
+			n, err := strconv.Atoi(input[1])
+			if err != nil {
+				in.Printf("invalid round %q\n", input[1])
+				continue
+			}
+			if err := v.jump(n); err != nil {
+				in.Println(err)
+				continue
+			}
+		case "a", "auto":
+			v.autoPlay(*delay)
+			continue
+		case "q", "quit":
+			return
+		default:
+			in.Println("Unknown command.")
+			continue
+		}
+		v.show()
+	}
+}
+
+// viewer tracks the position in the replayed session
+// Rounds are replayed through the engine when they are first shown.
+type viewer struct {
+	in     *game.InputSource
+	rounds []history.Entry
+	index  int // Index of the current round in rounds
+	frames []history.Frame
+	step   int // Index of the current frame in frames
+}
+
+// load replays the round at index i and moves to its first frame
+func (v *viewer) load(i int) error {
+	frames, err := history.Replay(v.rounds[i].RoundRecord)
+	if err != nil {
+		return err
+	}
+	v.index, v.frames, v.step = i, frames, 0
+	return nil
+}
+
+// jump moves to the first frame of the round with the given number
+func (v *viewer) jump(round int) error {
+	for i, entry := range v.rounds {
+		if entry.Round == round {
+			return v.load(i)
+		}
+	}
+	return fmt.Errorf("round %d is not in this session (rounds %d to %d)",
+		round, v.rounds[0].Round, v.rounds[len(v.rounds)-1].Round)
+}
+
+// next moves one frame forward, into the next round if needed
+func (v *viewer) next() bool {
+	if v.step < len(v.frames)-1 {
+		v.step++
+		return true
+	}
+	for i := v.index + 1; i < len(v.rounds); i++ {
+		if err := v.load(i); err != nil {
+			v.in.Printf("Skipping: %v\n", err)
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// prev moves one frame back, into the end of the previous round if needed
+func (v *viewer) prev() bool {
+	if v.step > 0 {
+		v.step--
+		return true
+	}
+	for i := v.index - 1; i >= 0; i-- {
+		if err := v.load(i); err != nil {
+			v.in.Printf("Skipping: %v\n", err)
+			continue
+		}
+		v.step = len(v.frames) - 1
+		return true
+	}
+	return false
+}
+
+// autoPlay shows every remaining frame with a pause between them
+func (v *viewer) autoPlay(delay time.Duration) {
+	for v.next() {
+		time.Sleep(delay)
+		v.show()
+	}
+	v.in.Println("\nEnd of session.")
+}
+
+// show prints the current frame
+func (v *viewer) show() {
+	frame := v.frames[v.step]
+	v.in.Println()
+	v.in.Printf("── Round %d, step %d of %d ──\n", frame.Round, v.step+1, len(v.frames))
+	v.in.Println(frame.Step)
+	if frame.Result != "" {
+		v.in.Printf("%s", frame.Result)
+		return
+	}
+	v.in.Println(frame.Table)
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// loadShoeFile reads the cards of a -shoe-file in the order they are to be dealt
+// Cards are written as for ParseCard, separated by spaces, commas or new lines, and
+// anything after a # on a line is a comment.
+func loadShoeFile(path string) ([]game.Card, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var cards []game.Card
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		parsed, err := game.ParseCards(strings.ToUpper(line))
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, n, err)
+		}
+		cards = append(cards, parsed...)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("%s: no cards to deal", path)
+	}
+	return cards, nil
+}
+
+// stackShoe has the game deal the cards in order, then a freshly shuffled shoe of the table's decks
+// Falling back to full decks also covers a round that runs out of stacked cards partway through.
+func stackShoe(g *game.Game, cards []game.Card) {
+	g.Shoe = game.NewStackedShoe(cards, g.RNG)
+	g.Shoe.RefillWith(g.Rules.NumDecks, g.Rules.Penetration)
+}
+
+// shoeFileSpent reports whether every card from the -shoe-file has been dealt
+func shoeFileSpent(g *game.Game) bool {
+	return g.Shoe.Refill == 0 || g.Shoe.Remaining() == 0
+}
+
+// shoeEndDescription says what happens once the -shoe-file cards run out
+func shoeEndDescription(end string) string {
+	if end == "shuffle" {
+		return "play on from a shuffled shoe"
+	}
+	return "stop"
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/history"
+	"github.com/DanDo385/blackjack-cli/internal/strategy"
+)
+
+// runTable runs a hot-seat session with several seats against one dealer
+// seats is a comma-separated list of players in seat order: a bot name seats that bot
+// and anything else seats a person of that name. Table sessions are not saved.
+func runTable(in *game.InputSource, rules game.RuleSet, seats string, seedFlag string, countName string, showCount bool, trainerMode bool, showEV bool, historyPath string) {
+	seed, err := newSeed(seedFlag, in.Printf)
+	if err != nil {
+		in.Println(err)
+		os.Exit(2)
+	}
+	t := game.NewTable(rules, seed)
+
+	var system game.CountSystem
+	if countName != "" {
+		system, err = game.ParseCountSystem(countName)
+		if err != nil {
+			in.Println(err)
+			os.Exit(2)
+		}
+	}
+
+	var trainer *strategy.Trainer
+	if trainerMode {
+		trainer = strategy.NewTrainer()
+	}
+
+	for _, name := range strings.Split(seats, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		seat, err := t.Sit(name)
+		if err != nil {
+			in.Println(err)
+			os.Exit(2)
+		}
+		if countName != "" {
+			seat.Game.EnableCounting(system)
+		}
+		if isBot(name) {
+			bot, err := strategy.NewBot(name, seat.Game, 10)
+			if err != nil {
+				in.Println(err)
+				os.Exit(2)
+			}
+			seat.Player = watched{Player: bot, name: name, in: in}
+		} else {
+			seat.Player = &human{in: in, trainer: trainer, countVisible: showCount, showEV: showEV, beforePrompt: func() {}, seat: seat, table: t}
+		}
+	}
+	if len(t.Seats) < 1 {
+		in.Println("-seats needs at least one player")
+		os.Exit(2)
+	}
+
+	in.Printf("Table: %s (%s)\n", t.Rules.Name, t.Rules.Summary())
+	in.Println(t.Rules.Soft17Rule())
+	in.Printf("🎲 Session seed: %d (replay with -seed %d)\n", t.Seed, t.Seed)
+	for i, seat := range t.Seats {
+		in.Printf("🪑 Seat %d: %s\n", i+1, seat.Name)
+	}
+	in.Println("Enter 'q' at your bet prompt to leave the table.")
+
+	var hist *history.Writer
+	if historyPath != "" {
+		hist, err = history.Open(historyPath, history.NewSessionID(seed))
+		if err != nil {
+			in.Printf("Hand history disabled: %v\n", err)
+		} else {
+			defer hist.Close()
+			in.Printf("📝 Hand history: %s\n", historyPath)
+		}
+	}
+
+	t.Subscribe(func(seat int, e game.Event) {
+		announce(in, e)
+	})
+
+	for {
+		err := t.PlayRound()
+		if errors.Is(err, game.ErrQuit) {
+			in.Println("\nEveryone has left the table.")
+			break
+		}
+		if err != nil {
+			in.Printf("\nError: %v\n", err)
+			break
+		}
+
+		in.Println(game.RenderTableResult(t))
+		for _, seat := range t.Seats {
+			// Only the seats dealt in this round have hands on the table
+			if len(seat.Game.PlayerHands) == 0 {
+				continue
+			}
+			checkBank(in, seat.Game)
+			writeHistory(in, hist, seat.Game)
+			if seat.Game.CurrentPhase == game.PhaseGameOver {
+				in.Printf("💸 %s is busted and leaves the table.\n", seat.Name)
+			}
+		}
+
+		if !stillPlaying(t) {
+			in.Println("\nEveryone has left the table.")
+			break
+		}
+		if !promptContinue(in) {
+			break
+		}
+	}
+
+	if trainer != nil {
+		in.Println()
+		in.Printf("%s", trainer.Report())
+	}
+
+	in.Println()
+	for i, seat := range t.Seats {
+		in.Printf("🏦 Seat %d, %s: %d chips\n", i+1, seat.Name, seat.Game.Bank)
+	}
+	in.Printf("🎲 Session seed: %d (replay with -seed %d)\n", t.Seed, t.Seed)
+	in.Println("\nThanks for playing!")
+}
+
+// isBot reports whether a seat name is the name of a bot
+func isBot(name string) bool {
+	for _, bot := range strategy.Bots {
+		if name == bot {
+			return true
+		}
+	}
+	return false
+}
+
+// stillPlaying reports whether any seat can play another round
+func stillPlaying(t *game.Table) bool {
+	for _, seat := range t.Playing() {
+		if seat.Game.CurrentPhase != game.PhaseGameOver {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/sim"
+)
+
+func main() {
+	rulesName := flag.String("rules", "classic", "table rules preset (classic, vegas-strip, atlantic-city, downtown, european)")
+	decks := flag.Int("decks", 0, "number of decks (overrides the preset)")
+	penetration := flag.Float64("penetration", 0, "fraction of the shoe dealt before the cut card (overrides the preset)")
+	soft17 := flag.String("soft17", "", "override the preset's soft 17 rule: \"hit\" (H17) or \"stand\" (S17)")
+	strategyName := flag.String("strategy", "basic", "playing strategy: basic, mimic or never-bust")
+	rounds := flag.Int("rounds", 1000000, "number of rounds to simulate")
+	seed := flag.Int64("seed", 1, "master random seed")
+	workers := flag.Int("workers", runtime.NumCPU(), "number of parallel simulation workers")
+	flag.Parse()
+
+	rules, err := game.RulesByName(*rulesName)
+	if err != nil {
+		fail(err)
+	}
+	if *decks != 0 {
+		rules.NumDecks = *decks
+	}
+	if *penetration != 0 {
+		rules.Penetration = *penetration
+	}
+	switch *soft17 {
+	case "":
+	case "hit", "h17":
+		rules.DealerHitsSoft17 = true
+	case "stand", "s17":
+		rules.DealerHitsSoft17 = false
+	default:
+		fail(fmt.Errorf("invalid -soft17 value %q (use \"hit\" or \"stand\")", *soft17))
+	}
+	if err := rules.Validate(); err != nil {
+		fail(err)
+	}
+
+	strat, err := sim.StrategyByName(*strategyName)
+	if err != nil {
+		fail(err)
+	}
+	if *rounds < 1 {
+		fail(fmt.Errorf("rounds must be at least 1"))
+	}
+
+	fmt.Printf("Rules:    %s (%s)\n", rules.Name, rules.Summary())
+	fmt.Printf("Strategy: %s\n", *strategyName)
+	fmt.Printf("Seed:     %d\n", *seed)
+	fmt.Println()
+
+	start := time.Now()
+	result := sim.Run(sim.Config{
+		Rules:    rules,
+		Strategy: strat,
+		Rounds:   *rounds,
+		Seed:     *seed,
+		Workers:  *workers,
+	})
+
+	fmt.Print(result.Report())
+	fmt.Fprintf(os.Stderr, "\nSimulated %d rounds on %d workers in %s\n", result.Rounds, *workers, time.Since(start).Round(time.Millisecond))
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(2)
+}
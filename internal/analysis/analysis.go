@@ -0,0 +1,434 @@
+// Package analysis computes the exact expected value of each play in a blackjack decision
+// Rather than simulating, it enumerates every way the rest of the shoe can be dealt,
+// card by card, from the exact composition left once the known cards are taken out.
+package analysis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// EV is the expected value of one action, in units of the hand's bet
+type EV struct {
+	Action game.Action
+	Value  float64
+}
+
+// Analysis is the expected value of every action in a decision, best first
+type Analysis struct {
+	EVs []EV
+}
+
+// Best returns the action with the highest expected value
+func (a Analysis) Best() EV {
+	return a.EVs[0]
+}
+
+// EV returns the expected value of the action, in units of the bet
+// An action that wasn't analysed returns false.
+func (a Analysis) EV(action game.Action) (float64, bool) {
+	for _, ev := range a.EVs {
+		if ev.Action == action {
+			return ev.Value, true
+		}
+	}
+	return 0, false
+}
+
+// Cost returns how much the action gives up against the best one, in units of the bet
+// An action that wasn't analysed returns false.
+func (a Analysis) Cost(action game.Action) (float64, bool) {
+	value, ok := a.EV(action)
+	if !ok {
+		return 0, false
+	}
+	return a.Best().Value - value, true
+}
+
+// String lists the expected values, best first, e.g. "stand -0.154, hit -0.213 (-0.059)"
+// When split is listed the string says that its value is approximated.
+func (a Analysis) String() string {
+	parts := make([]string, len(a.EVs))
+	for i, ev := range a.EVs {
+		parts[i] = fmt.Sprintf("%s %+.3f", strings.ToLower(ev.Action.String()), ev.Value)
+		if i > 0 {
+			parts[i] += fmt.Sprintf(" (%+.3f)", ev.Value-a.Best().Value)
+		}
+	}
+	s := strings.Join(parts, ", ")
+	if _, ok := a.EV(game.ActionSplit); ok {
+		s += "; split is approximated, without resplits"
+	}
+	return s
+}
+
+// Analyze computes the expected value of each available action for the hand
+// The shoe is the table's decks less the hand, the dealer's upcard and the removed cards
+// (the discard tray and any other cards in sight). Decisions are valued as the player
+// meets them: after the dealer has peeked for blackjack where the rules say so, and
+// before it under early surrender. With available nil, every action the hand allows is
+// valued.
+//
+// Split is approximated: it is valued as two hands each played from the shoe as it stands
+// after the split, and neither hand is split again. Every other action is exact.
+func Analyze(hand *game.Hand, upcard game.Card, removed []game.Card, rules game.RuleSet, available []game.Action) (Analysis, error) {
+	s, err := newShoe(rules.NumDecks)
+	if err != nil {
+		return Analysis{}, err
+	}
+	for _, cards := range [][]game.Card{hand.Cards, {upcard}, removed} {
+		for _, card := range cards {
+			if err := s.remove(card); err != nil {
+				return Analysis{}, err
+			}
+		}
+	}
+	if available == nil {
+		available = actions(hand, rules)
+	}
+	if len(available) == 0 {
+		return Analysis{}, fmt.Errorf("no actions to analyse")
+	}
+
+	up := value(upcard)
+	a := &analyzer{
+		rules:  rules,
+		upcard: up,
+		peek:   rules.DealerPeeks && (up == 1 || up == 10),
+		dealer: map[handKey]outcomes{},
+		odds:   map[shoe]outcomes{},
+		played: map[handKey]float64{},
+	}
+	// Under early surrender the peek comes after the player has had the chance to surrender
+	prePeek := a.peek && rules.Surrender == game.SurrenderEarly && hand.CanSurrender(rules)
+
+	hard, aces := 0, false
+	for _, card := range hand.Cards {
+		hard += value(card)
+		aces = aces || card.IsAce()
+	}
+
+	var result Analysis
+	for _, action := range available {
+		var ev float64
+		switch action {
+		case game.ActionStand:
+			ev = a.stand(s, total(hard, aces))
+		case game.ActionHit:
+			ev = a.hit(s, hard, aces)
+		case game.ActionDouble:
+			ev = a.double(s, hard, aces)
+		case game.ActionSplit:
+			ev = a.split(s, hand.Cards[0])
+		case game.ActionSurrender:
+			result.EVs = append(result.EVs, EV{Action: action, Value: -0.5})
+			continue
+		default:
+			return Analysis{}, fmt.Errorf("can't analyse %s", action)
+		}
+		if prePeek {
+			// The dealer's blackjack takes only the original bet once the player plays on
+			natural := float64(s.naturals(up)) / float64(s.total)
+			ev = natural*-1 + (1-natural)*ev
+		}
+		result.EVs = append(result.EVs, EV{Action: action, Value: ev})
+	}
+
+	sort.SliceStable(result.EVs, func(i, j int) bool {
+		return result.EVs[i].Value > result.EVs[j].Value
+	})
+	return result, nil
+}
+
+// actions returns every action the hand allows under the rules
+func actions(hand *game.Hand, rules game.RuleSet) []game.Action {
+	if hand.Value() >= 21 {
+		return nil
+	}
+	if hand.IsSplitAces && len(hand.Cards) >= 2 {
+		return []game.Action{game.ActionStand}
+	}
+	available := []game.Action{game.ActionHit, game.ActionStand}
+	if hand.CanDouble(rules) {
+		available = append(available, game.ActionDouble)
+	}
+	if hand.CanSplit(rules) {
+		available = append(available, game.ActionSplit)
+	}
+	if hand.CanSurrender(rules) {
+		available = append(available, game.ActionSurrender)
+	}
+	return available
+}
+
+// shoe is the composition of the undealt cards, counted by blackjack value with aces as 1
+type shoe struct {
+	counts [11]int
+	total  int
+}
+
+func newShoe(decks int) (shoe, error) {
+	if decks < 1 {
+		return shoe{}, fmt.Errorf("the rules need at least one deck")
+	}
+	var s shoe
+	for v := 1; v <= 9; v++ {
+		s.counts[v] = 4 * decks
+	}
+	s.counts[10] = 16 * decks
+	s.total = 52 * decks
+	return s, nil
+}
+
+// remove takes a known card out of the shoe
+func (s *shoe) remove(card game.Card) error {
+	v := value(card)
+	if s.counts[v] == 0 {
+		return fmt.Errorf("no %s left in the shoe to remove", card)
+	}
+	s.counts[v]--
+	s.total--
+	return nil
+}
+
+// draw returns the shoe with one card of value v dealt from it
+func (s shoe) draw(v int) shoe {
+	s.counts[v]--
+	s.total--
+	return s
+}
+
+// naturals returns how many hole cards would give the dealer blackjack under the upcard
+func (s shoe) naturals(upcard int) int {
+	switch upcard {
+	case 1:
+		return s.counts[10]
+	case 10:
+		return s.counts[1]
+	default:
+		return 0
+	}
+}
+
+// value returns the card's blackjack value with an ace counted as 1
+func value(card game.Card) int {
+	if card.IsAce() {
+		return 1
+	}
+	return card.Rank.Value()
+}
+
+// total returns the best total of a hand from its hard total and whether it holds an ace
+func total(hard int, aces bool) int {
+	if aces && hard+10 <= 21 {
+		return hard + 10
+	}
+	return hard
+}
+
+// outcomes is the chance of each way the dealer's hand can finish
+type outcomes [7]float64
+
+// Indexes into outcomes; the final totals 17 to 21 are at 0 to 4
+const (
+	dealerBust      = 5
+	dealerBlackjack = 6
+)
+
+// handKey identifies a hand, the player's or the dealer's, and the shoe it is dealt from
+type handKey struct {
+	s    shoe
+	hard int
+	aces bool
+}
+
+// analyzer holds one decision's rules and the results worked out so far
+// Outcomes are memoized by shoe composition, so paths that deal the same cards in a
+// different order are only worked out once.
+type analyzer struct {
+	rules  game.RuleSet
+	upcard int  // Value of the dealer's upcard
+	peek   bool // Decisions are made knowing the dealer doesn't have blackjack
+
+	dealer map[handKey]outcomes
+	odds   map[shoe]outcomes
+	played map[handKey]float64
+}
+
+// dealerOdds returns how the dealer's hand finishes when dealt from the shoe
+// The hole card is drawn first; when the dealer has peeked it can't make blackjack.
+func (a *analyzer) dealerOdds(s shoe) outcomes {
+	if o, ok := a.odds[s]; ok {
+		return o
+	}
+
+	var o outcomes
+	natural := 0
+	if a.upcard == 1 {
+		natural = 10
+	} else if a.upcard == 10 {
+		natural = 1
+	}
+	possible := s.total
+	if a.peek && natural != 0 {
+		possible -= s.counts[natural]
+	}
+	for v := 1; v <= 10; v++ {
+		n := s.counts[v]
+		if n == 0 {
+			continue
+		}
+		p := float64(n) / float64(possible)
+		if v == natural {
+			if !a.peek {
+				o[dealerBlackjack] += p
+			}
+			continue
+		}
+		sub := a.dealerDraw(s.draw(v), a.upcard+v, a.upcard == 1 || v == 1)
+		for i := range o {
+			o[i] += p * sub[i]
+		}
+	}
+
+	a.odds[s] = o
+	return o
+}
+
+// dealerDraw plays out the dealer's hand from the shoe under the soft 17 rule
+func (a *analyzer) dealerDraw(s shoe, hard int, aces bool) outcomes {
+	var o outcomes
+	value := total(hard, aces)
+	soft := value != hard
+	if value > 21 {
+		o[dealerBust] = 1
+		return o
+	}
+	if value > 17 || value == 17 && !(soft && a.rules.DealerHitsSoft17) {
+		o[value-17] = 1
+		return o
+	}
+
+	key := handKey{s, hard, aces}
+	if cached, ok := a.dealer[key]; ok {
+		return cached
+	}
+	for v := 1; v <= 10; v++ {
+		n := s.counts[v]
+		if n == 0 {
+			continue
+		}
+		p := float64(n) / float64(s.total)
+		sub := a.dealerDraw(s.draw(v), hard+v, aces || v == 1)
+		for i := range o {
+			o[i] += p * sub[i]
+		}
+	}
+	a.dealer[key] = o
+	return o
+}
+
+// stand returns the expected value of standing on the total with the shoe as it is
+func (a *analyzer) stand(s shoe, value int) float64 {
+	if value > 21 {
+		return -1
+	}
+	o := a.dealerOdds(s)
+	ev := o[dealerBust] - o[dealerBlackjack]
+	for i := 0; i < 5; i++ {
+		switch dealer := 17 + i; {
+		case value > dealer:
+			ev += o[i]
+		case value < dealer:
+			ev -= o[i]
+		}
+	}
+	return ev
+}
+
+// hit returns the expected value of taking one card and then playing on as well as possible
+func (a *analyzer) hit(s shoe, hard int, aces bool) float64 {
+	ev := 0.0
+	for v := 1; v <= 10; v++ {
+		n := s.counts[v]
+		if n == 0 {
+			continue
+		}
+		ev += float64(n) / float64(s.total) * a.play(s.draw(v), hard+v, aces || v == 1)
+	}
+	return ev
+}
+
+// play returns the expected value of the better of standing and hitting
+func (a *analyzer) play(s shoe, hard int, aces bool) float64 {
+	value := total(hard, aces)
+	if value > 21 {
+		return -1
+	}
+	if value == 21 {
+		return a.stand(s, value)
+	}
+
+	key := handKey{s, hard, aces}
+	if ev, ok := a.played[key]; ok {
+		return ev
+	}
+	ev := a.stand(s, value)
+	if hit := a.hit(s, hard, aces); hit > ev {
+		ev = hit
+	}
+	a.played[key] = ev
+	return ev
+}
+
+// double returns the expected value of doubling the bet for exactly one more card
+func (a *analyzer) double(s shoe, hard int, aces bool) float64 {
+	ev := 0.0
+	for v := 1; v <= 10; v++ {
+		n := s.counts[v]
+		if n == 0 {
+			continue
+		}
+		ev += float64(n) / float64(s.total) * 2 * a.stand(s.draw(v), total(hard+v, aces || v == 1))
+	}
+	return ev
+}
+
+// split returns the expected value of splitting the pair into two hands
+// Each hand is dealt its second card from the shoe left after the split and then played
+// as well as the rules allow, short of splitting again.
+func (a *analyzer) split(s shoe, card game.Card) float64 {
+	pair := value(card)
+	ev := 0.0
+	for v := 1; v <= 10; v++ {
+		n := s.counts[v]
+		if n == 0 {
+			continue
+		}
+		next := s.draw(v)
+		hard, aces := pair+v, pair == 1 || v == 1
+
+		// Split aces get one card and stand; a ten on one makes 21, not blackjack
+		best := a.stand(next, total(hard, aces))
+		if !card.IsAce() {
+			if hit := a.hit(next, hard, aces); hit > best {
+				best = hit
+			}
+			dealt := &game.Hand{
+				Cards:         []game.Card{card, {Rank: game.Rank(v)}},
+				IsInitialDeal: true,
+				IsFromSplit:   true,
+			}
+			if dealt.CanDouble(a.rules) {
+				if double := a.double(next, hard, aces); double > best {
+					best = double
+				}
+			}
+		}
+		ev += float64(n) / float64(s.total) * best
+	}
+	return 2 * ev
+}
@@ -0,0 +1,160 @@
+package analysis
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// analyze analyses a hand written in ParseCards notation against the upcard
+func analyze(t *testing.T, rules game.RuleSet, hand string, upcard string, removed string) (Analysis, error) {
+	t.Helper()
+	cards, err := game.ParseCards(hand)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := game.NewHand(10)
+	for _, card := range cards {
+		h.Add(card)
+	}
+	up, err := game.ParseCard(upcard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gone []game.Card
+	if removed != "" {
+		if gone, err = game.ParseCards(removed); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return Analyze(h, up, gone, rules, nil)
+}
+
+func rules(t *testing.T, name string) game.RuleSet {
+	t.Helper()
+	r, err := game.RulesByName(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// TestPinnedValues pins the expected values this package computes for some common decisions
+// from a full shoe, so that a change to the enumeration shows up. The figures are this
+// package's own output, not taken from a published table.
+func TestPinnedValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		rules  string
+		hand   string
+		upcard string
+		best   game.Action
+		values map[game.Action]float64
+	}{
+		{"hard 16 vs 10", "classic", "10S 6C", "10H", game.ActionSurrender,
+			map[game.Action]float64{game.ActionStand: -0.543, game.ActionHit: -0.507, game.ActionSurrender: -0.5}},
+		{"11 vs 6", "classic", "5S 6C", "6H", game.ActionDouble,
+			map[game.Action]float64{game.ActionDouble: 0.761, game.ActionHit: 0.381, game.ActionStand: -0.139}},
+		{"11 vs 10", "classic", "5S 6C", "10H", game.ActionDouble,
+			map[game.Action]float64{game.ActionDouble: 0.173, game.ActionHit: 0.115}},
+		{"8,8 vs 10", "vegas-strip", "8S 8C", "10H", game.ActionSplit,
+			map[game.Action]float64{game.ActionSplit: -0.480, game.ActionStand: -0.535}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := analyze(t, rules(t, tt.rules), tt.hand, tt.upcard, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Best().Action != tt.best {
+				t.Errorf("best play is %s, expected %s (%s)", a.Best().Action, tt.best, a)
+			}
+			for action, want := range tt.values {
+				got, ok := a.EV(action)
+				if !ok {
+					t.Errorf("%s wasn't analysed", action)
+					continue
+				}
+				if math.Abs(got-want) > 0.0005 {
+					t.Errorf("%s is %+.4f, expected %+.3f", action, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestEarlySurrenderBeforePeek checks that decisions under early surrender carry the
+// dealer's blackjack, which takes only the original bet
+func TestEarlySurrenderBeforePeek(t *testing.T) {
+	late := rules(t, "classic")
+	early := late
+	early.Surrender = game.SurrenderEarly
+
+	peeked, err := analyze(t, late, "10S 6C", "AH", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, err := analyze(t, early, "10S 6C", "AH", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 15 of the 49 unseen cards give the dealer blackjack under the ace
+	natural := 15.0 / 49.0
+	for _, ev := range peeked.EVs {
+		want := -natural + (1-natural)*ev.Value
+		if ev.Action == game.ActionSurrender {
+			want = -0.5
+		}
+		got, _ := before.EV(ev.Action)
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("%s before the peek is %+.4f, expected %+.4f", ev.Action, got, want)
+		}
+	}
+	if before.Best().Action != game.ActionSurrender {
+		t.Errorf("best play before the peek is %s, expected surrender (%s)", before.Best().Action, before)
+	}
+}
+
+func TestCost(t *testing.T) {
+	a, err := analyze(t, rules(t, "classic"), "10S 6C", "10H", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost, ok := a.Cost(game.ActionSurrender); !ok || cost != 0 {
+		t.Errorf("the best play costs %v (%v), expected 0", cost, ok)
+	}
+	if cost, ok := a.Cost(game.ActionStand); !ok || math.Abs(cost-0.043) > 0.0005 {
+		t.Errorf("standing costs %.4f (%v), expected 0.043", cost, ok)
+	}
+	if _, ok := a.Cost(game.ActionSplit); ok {
+		t.Error("split has a cost but 16 can't be split")
+	}
+}
+
+func TestStringNotesSplitApproximation(t *testing.T) {
+	pair, err := analyze(t, rules(t, "classic"), "8S 8C", "10H", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(pair.String(), "split is approximated") {
+		t.Errorf("%q doesn't say split is approximated", pair)
+	}
+	hard, err := analyze(t, rules(t, "classic"), "10S 6C", "10H", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(hard.String(), "approximated") {
+		t.Errorf("%q mentions an approximation without a split", hard)
+	}
+}
+
+func TestImpossibleComposition(t *testing.T) {
+	// A single deck has four aces, not five
+	_, err := analyze(t, rules(t, "classic"), "10S 6C", "10H", "AS AH AD AC AS")
+	if err == nil || !strings.Contains(err.Error(), "left in the shoe to remove") {
+		t.Errorf("expected the fifth ace to be refused, got %v", err)
+	}
+}
@@ -1,6 +1,9 @@
 package game
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Suit represents a card suit
 type Suit int
@@ -118,6 +121,39 @@ func (c Card) String() string {
 	return fmt.Sprintf("%s%s", c.Rank, c.Suit)
 }
 
+// Code returns the card in ParseCard notation (e.g., "KC", "10H")
+func (c Card) Code() string {
+	var suit string
+	switch c.Suit {
+	case Clubs:
+		suit = "C"
+	case Diamonds:
+		suit = "D"
+	case Hearts:
+		suit = "H"
+	case Spades:
+		suit = "S"
+	default:
+		suit = "?"
+	}
+	return c.Rank.String() + suit
+}
+
+// MarshalText encodes the card in ParseCard notation
+func (c Card) MarshalText() ([]byte, error) {
+	return []byte(c.Code()), nil
+}
+
+// UnmarshalText decodes a card in ParseCard notation
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
 // IsAce returns true if the card is an Ace
 func (c Card) IsAce() bool {
 	return c.Rank == Ace
@@ -186,3 +222,19 @@ func ParseCard(s string) (Card, error) {
 
 	return Card{Rank: rank, Suit: suit}, nil
 }
+
+// ParseCards parses a list of cards such as "AS KD 8H 8C", separated by spaces or commas
+func ParseCards(s string) ([]Card, error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	})
+	cards := make([]Card, 0, len(fields))
+	for _, field := range fields {
+		card, err := ParseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
@@ -3,6 +3,7 @@ package game
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 // RenderState renders the current game state
@@ -10,58 +11,39 @@ func RenderState(g *Game, hideDealerHole bool) string {
 	var sb strings.Builder
 
 	sb.WriteString("+------------------------------------------+\n")
+	sb.WriteString(renderDealerRow(g.Rules, g.DealerHand, hideDealerHole))
 
-	// Render dealer hand
-	sb.WriteString("| Dealer: ")
-	if hideDealerHole && len(g.DealerHand.Cards) >= 2 {
-		// Hide hole card
-		sb.WriteString("[??, ")
-		for i := 1; i < len(g.DealerHand.Cards); i++ {
-			sb.WriteString(g.DealerHand.Cards[i].String())
-			if i < len(g.DealerHand.Cards)-1 {
-				sb.WriteString(", ")
+	// Several spots get a compact row each, with the spot being played marked
+	if g.Spots() > 1 {
+		for i, hand := range g.PlayerHands {
+			marker := " "
+			if i == g.ActiveHandIndex && (g.CurrentPhase == PhasePlayerAction || g.CurrentPhase == PhaseInsurance) {
+				marker = ">"
 			}
+			sb.WriteString(padRow(fmt.Sprintf("|%s%s: %s", marker, spotLabel(g, i), renderHandValue(hand))))
 		}
-		sb.WriteString("]")
-	} else {
-		// Show all cards
-		sb.WriteString(g.DealerHand.String())
-		if !hideDealerHole {
-			sb.WriteString(fmt.Sprintf(" (%d)", g.DealerHand.Value()))
-		}
-	}
-	// Pad to column width (41 chars total including closing |)
-	lineLen := len(sb.String()) - strings.LastIndex(sb.String(), "\n") - 1
-	if lineLen < 41 {
-		sb.WriteString(strings.Repeat(" ", 41-lineLen))
+		sb.WriteString("+------------------------------------------+")
+		return sb.String()
 	}
-	sb.WriteString("|\n")
 
 	// Render player hands
 	for i, hand := range g.PlayerHands {
+		row := "| You: "
 		if len(g.PlayerHands) > 1 {
-			sb.WriteString(fmt.Sprintf("| You (Hand %d/%d): ", i+1, len(g.PlayerHands)))
-		} else {
-			sb.WriteString("| You: ")
+			row = fmt.Sprintf("| You (Hand %d/%d): ", i+1, len(g.PlayerHands))
 		}
 
-		sb.WriteString(hand.String())
+		row += hand.String()
 		if !hand.IsBust() {
-			sb.WriteString(fmt.Sprintf(" (%d)", hand.Value()))
+			row += fmt.Sprintf(" (%d)", hand.Value())
 		} else {
-			sb.WriteString(" (BUST)")
+			row += " (BUST)"
 		}
 
 		if hand.Surrendered {
-			sb.WriteString(" [SURRENDERED]")
+			row += " [SURRENDERED]"
 		}
-
-		// Pad to column width
-		lineLen := len(sb.String()) - strings.LastIndex(sb.String(), "\n") - 1
-		if lineLen < 41 {
-			sb.WriteString(strings.Repeat(" ", 41-lineLen))
-		}
-		sb.WriteString("|\n")
+		sb.WriteString(padRow(row))
 	}
 
 	sb.WriteString("+------------------------------------------+")
@@ -69,6 +51,38 @@ func RenderState(g *Game, hideDealerHole bool) string {
 	return sb.String()
 }
 
+// RenderSeed renders the session seed and how to replay it
+func RenderSeed(g *Game) string {
+	return fmt.Sprintf("🎲 Session seed: %d (replay with -seed %d)", g.Seed, g.Seed)
+}
+
+// RenderShoe renders how far the shoe has been dealt
+func RenderShoe(g *Game) string {
+	if g.Shoe.Refill > 0 {
+		return fmt.Sprintf("Shoe: %d stacked cards left, %d in the discard tray",
+			g.Shoe.Remaining(), len(g.Shoe.Discards))
+	}
+	total := g.Shoe.NumDecks * 52
+	dealt := total - g.Shoe.Remaining()
+	return fmt.Sprintf("Shoe: %d of %d cards dealt (%.0f%%), %d in the discard tray",
+		dealt, total, float64(dealt)*100/float64(total), len(g.Shoe.Discards))
+}
+
+// RenderCount renders the running and true count
+func RenderCount(g *Game) string {
+	if g.Counter == nil {
+		return "Card counting is off."
+	}
+
+	decks := g.Shoe.DecksRemaining()
+	if !g.Counter.System.Balanced() {
+		return fmt.Sprintf("%s running count: %+d (%d cards seen, %.1f decks left)",
+			g.Counter.System, g.Counter.Running, g.Counter.Seen, decks)
+	}
+	return fmt.Sprintf("%s running count: %+d, true count: %+.1f (%.1f decks left)",
+		g.Counter.System, g.Counter.Running, g.Counter.TrueCount(decks), decks)
+}
+
 // RenderCurrentHand displays information about the hand currently being played
 func RenderCurrentHand(g *Game) string {
 	if g.ActiveHandIndex >= len(g.PlayerHands) {
@@ -80,7 +94,11 @@ func RenderCurrentHand(g *Game) string {
 	handNum := g.ActiveHandIndex + 1
 
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Playing Hand %d of %d\n", handNum, totalHands))
+	if g.Spots() > 1 {
+		sb.WriteString(fmt.Sprintf("Playing %s\n", spotLabel(g, g.ActiveHandIndex)))
+	} else {
+		sb.WriteString(fmt.Sprintf("Playing Hand %d of %d\n", handNum, totalHands))
+	}
 	sb.WriteString(fmt.Sprintf("Current cards: %s\n", hand.String()))
 
 	if hand.IsBust() {
@@ -127,16 +145,157 @@ func RenderResult(g *Game) string {
 	sb.WriteString("\n" + RenderState(g, false) + "\n\n")
 	sb.WriteString("Results:\n")
 
+	sb.WriteString(renderOutcomes(g))
+
+	sb.WriteString("\n" + g.Rules.Soft17Rule())
+	if dealerHitSoft17(g.DealerHand) {
+		sb.WriteString(" - the dealer hit a soft 17 this hand")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(fmt.Sprintf("\nBank: %d chips\n", g.Bank))
+
+	return sb.String()
+}
+
+// renderDealerRow renders the dealer's row of the table, labelled with the soft 17 rule in force
+func renderDealerRow(rules RuleSet, dealer *Hand, hideDealerHole bool) string {
+	var sb strings.Builder
+	if rules.DealerHitsSoft17 {
+		sb.WriteString("| Dealer (H17): ")
+	} else {
+		sb.WriteString("| Dealer (S17): ")
+	}
+	if hideDealerHole && len(dealer.Cards) >= 2 {
+		// Hide hole card
+		sb.WriteString("[??, ")
+		for i := 1; i < len(dealer.Cards); i++ {
+			sb.WriteString(dealer.Cards[i].String())
+			if i < len(dealer.Cards)-1 {
+				sb.WriteString(", ")
+			}
+		}
+		sb.WriteString("]")
+	} else {
+		// Show all cards
+		sb.WriteString(dealer.String())
+		if !hideDealerHole {
+			sb.WriteString(fmt.Sprintf(" (%d)", dealer.Value()))
+		}
+	}
+	return padRow(sb.String())
+}
+
+// padRow pads a row of the table to the column width and closes it
+func padRow(row string) string {
+	// Pad to the width of the border, counting runes since the suits take several bytes
+	if n := utf8.RuneCountInString(row); n < 43 {
+		row += strings.Repeat(" ", 43-n)
+	}
+	return row + "|\n"
+}
+
+// RenderTable renders every seat at a multi-seat table, marking the seat whose turn it is
+func RenderTable(t *Table, hideDealerHole bool, active *Seat) string {
+	var sb strings.Builder
+
+	sb.WriteString("+------------------------------------------+\n")
+	sb.WriteString(renderDealerRow(t.Rules, t.DealerHand, hideDealerHole))
+
+	for i, seat := range t.Seats {
+		marker := " "
+		if seat == active {
+			marker = ">"
+		}
+		g := seat.Game
+		if seat.Left || len(g.PlayerHands) == 0 {
+			sb.WriteString(padRow(fmt.Sprintf("|%s%d %s: (out, bank %d)", marker, i+1, seat.Name, g.Bank)))
+			continue
+		}
+		for j, hand := range g.PlayerHands {
+			label := fmt.Sprintf("|%s%d %s: ", marker, i+1, seat.Name)
+			if len(g.PlayerHands) > 1 {
+				label = fmt.Sprintf("|%s%d %s (Hand %d/%d): ", marker, i+1, seat.Name, j+1, len(g.PlayerHands))
+			}
+			sb.WriteString(padRow(label + renderHandValue(hand)))
+		}
+	}
+
+	sb.WriteString("+------------------------------------------+")
+
+	return sb.String()
+}
+
+// RenderTableResult renders the table and each seat's results once a round is resolved
+func RenderTableResult(t *Table) string {
+	var sb strings.Builder
+
+	sb.WriteString("\n" + RenderTable(t, false, nil) + "\n")
+
+	for i, seat := range t.Seats {
+		g := seat.Game
+		if seat.Left || len(g.PlayerHands) == 0 {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("\nSeat %d, %s:\n", i+1, seat.Name))
+		sb.WriteString(renderOutcomes(g))
+		sb.WriteString(fmt.Sprintf("  Bank: %d chips\n", g.Bank))
+	}
+
+	sb.WriteString("\n" + t.Rules.Soft17Rule())
+	if dealerHitSoft17(t.DealerHand) {
+		sb.WriteString(" - the dealer hit a soft 17 this hand")
+	}
+	sb.WriteString("\n")
+
+	return sb.String()
+}
+
+// renderHandValue renders a hand's cards with its value, or BUST, and whether it was surrendered
+func renderHandValue(hand *Hand) string {
+	row := hand.String()
+	if hand.IsBust() {
+		row += " (BUST)"
+	} else {
+		row += fmt.Sprintf(" (%d)", hand.Value())
+	}
+	if hand.Surrendered {
+		row += " [SURRENDERED]"
+	}
+	return row
+}
+
+// spotLabel labels a hand by its spot, and by its place among the spot's hands once split
+func spotLabel(g *Game, i int) string {
+	spot := g.PlayerHands[i].Spot
+	n := g.spotHands(spot)
+	if n == 1 {
+		return fmt.Sprintf("Spot %d", spot+1)
+	}
+	k := 0
+	for _, hand := range g.PlayerHands[:i+1] {
+		if hand.Spot == spot {
+			k++
+		}
+	}
+	return fmt.Sprintf("Spot %d (%d/%d)", spot+1, k, n)
+}
+
+// renderOutcomes renders one line for each hand's outcome and insurance
+func renderOutcomes(g *Game) string {
+	var sb strings.Builder
 	for i, hand := range g.PlayerHands {
 		handLabel := ""
-		if len(g.PlayerHands) > 1 {
+		if g.Spots() > 1 {
+			handLabel = spotLabel(g, i) + ": "
+		} else if len(g.PlayerHands) > 1 {
 			handLabel = fmt.Sprintf("Hand %d/%d: ", i+1, len(g.PlayerHands))
 		}
 
 		// Check insurance first
 		if hand.InsuranceBet > 0 {
 			if g.DealerHasBlackjack {
-				insuranceWin := Payout(OutcomeWin, hand.InsuranceBet, true)
+				insuranceWin := g.Rules.Payout(OutcomeWin, hand.InsuranceBet, true) - hand.InsuranceBet
 				sb.WriteString(fmt.Sprintf("  %sInsurance pays %d chips\n", handLabel, insuranceWin))
 			} else {
 				sb.WriteString(fmt.Sprintf("  %sInsurance loses %d chips\n", handLabel, hand.InsuranceBet))
@@ -145,7 +304,7 @@ func RenderResult(g *Game) string {
 
 		// Main hand outcome
 		outcome := DetermineOutcome(hand, g.DealerHand)
-		payout := Payout(outcome, hand.Bet, false)
+		payout := g.Rules.Payout(outcome, hand.Bet, false)
 
 		switch outcome {
 		case OutcomeBlackjack:
@@ -160,8 +319,16 @@ func RenderResult(g *Game) string {
 			sb.WriteString(fmt.Sprintf("  %sSurrender! Returns %d chips\n", handLabel, payout))
 		}
 	}
-
-	sb.WriteString(fmt.Sprintf("\nBank: %d chips\n", g.Bank))
-
 	return sb.String()
 }
+
+// dealerHitSoft17 returns true if the dealer drew a card while holding soft 17
+func dealerHitSoft17(dealer *Hand) bool {
+	for n := 2; n < len(dealer.Cards); n++ {
+		partial := &Hand{Cards: dealer.Cards[:n]}
+		if partial.Value() == 17 && partial.IsSoft() {
+			return true
+		}
+	}
+	return false
+}
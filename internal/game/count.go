@@ -0,0 +1,143 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CountSystem represents a card counting system
+type CountSystem int
+
+const (
+	CountHiLo CountSystem = iota
+	CountKO
+	CountHiOptII
+	CountOmegaII
+	CountZen
+)
+
+func (c CountSystem) String() string {
+	switch c {
+	case CountHiLo:
+		return "Hi-Lo"
+	case CountKO:
+		return "KO"
+	case CountHiOptII:
+		return "Hi-Opt II"
+	case CountOmegaII:
+		return "Omega II"
+	case CountZen:
+		return "Zen"
+	default:
+		return "Unknown"
+	}
+}
+
+// countTags holds the tag of each card for every system, indexed by blackjack value
+// (2-9, 10 for ten-value cards and 11 for Aces; entries 0 and 1 are unused)
+var countTags = map[CountSystem][12]int{
+	CountHiLo:    {0, 0, 1, 1, 1, 1, 1, 0, 0, 0, -1, -1},
+	CountKO:      {0, 0, 1, 1, 1, 1, 1, 1, 0, 0, -1, -1},
+	CountHiOptII: {0, 0, 1, 1, 2, 2, 1, 1, 0, 0, -2, 0},
+	CountOmegaII: {0, 0, 1, 1, 2, 2, 2, 1, 0, -1, -2, 0},
+	CountZen:     {0, 0, 1, 1, 2, 2, 2, 1, 0, 0, -2, -1},
+}
+
+// ParseCountSystem parses a counting system name such as "hilo", "ko", "hiopt2", "omega2" or "zen"
+func ParseCountSystem(name string) (CountSystem, error) {
+	key := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToLower(name))
+	switch key {
+	case "hilo":
+		return CountHiLo, nil
+	case "ko":
+		return CountKO, nil
+	case "hiopt2", "hioptii":
+		return CountHiOptII, nil
+	case "omega2", "omegaii":
+		return CountOmegaII, nil
+	case "zen":
+		return CountZen, nil
+	default:
+		return 0, fmt.Errorf("unknown count system %q (use hilo, ko, hiopt2, omega2 or zen)", name)
+	}
+}
+
+// MarshalText encodes the system by name
+func (c CountSystem) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText decodes a system name
+func (c *CountSystem) UnmarshalText(text []byte) error {
+	system, err := ParseCountSystem(string(text))
+	if err != nil {
+		return err
+	}
+	*c = system
+	return nil
+}
+
+// Tag returns the count value of a card in the system
+func (c CountSystem) Tag(card Card) int {
+	return countTags[c][card.Rank.Value()]
+}
+
+// Balanced returns true if a full shoe counts to zero, so a true count is meaningful
+func (c CountSystem) Balanced() bool {
+	return c != CountKO
+}
+
+// Counter keeps the running count of every card seen since the last shuffle
+type Counter struct {
+	System   CountSystem `json:"system"`
+	NumDecks int         `json:"num_decks"`
+	Running  int         `json:"running"`
+	Seen     int         `json:"seen"`
+}
+
+// NewCounter creates a counter for a shoe of numDecks decks
+func NewCounter(system CountSystem, numDecks int) *Counter {
+	c := &Counter{System: system, NumDecks: numDecks}
+	c.Reset()
+	return c
+}
+
+// Reset starts the count over after a shuffle
+// Unbalanced systems start at their initial running count (4 - 4 x decks for KO).
+func (c *Counter) Reset() {
+	c.Running = 0
+	c.Seen = 0
+	if c.System == CountKO {
+		c.Running = 4 - 4*c.NumDecks
+	}
+}
+
+// Observe adds a card that has been seen face up to the count
+func (c *Counter) Observe(card Card) {
+	c.Running += c.System.Tag(card)
+	c.Seen++
+}
+
+// Handle updates the count from the game's events, so a counter can follow any game
+// Cards are counted as they are seen: the hole card once it is turned over.
+func (c *Counter) Handle(e Event) {
+	switch e := e.(type) {
+	case CardDealt:
+		if !e.FaceDown {
+			c.Observe(e.Card)
+		}
+	case HoleCardRevealed:
+		c.Observe(e.Card)
+	case ShoeShuffled:
+		c.Reset()
+	}
+}
+
+// TrueCount returns the running count divided by the decks remaining
+// Fewer than half a deck is treated as half a deck to keep the estimate stable.
+func (c *Counter) TrueCount(decksRemaining float64) float64 {
+	if decksRemaining < 0.5 {
+		decksRemaining = 0.5
+	}
+	return float64(c.Running) / decksRemaining
+}
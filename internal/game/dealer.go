@@ -12,26 +12,12 @@ func PeekForBlackjack(upCard, downCard Card) bool {
 	return hand.IsBlackjack()
 }
 
-// DealerPlay plays out the dealer's hand according to S17 rules
-func DealerPlay(deck *[]Card, hand *Hand) {
-	// Dealer draws to 17, stands on soft 17
-	for {
-		value := hand.Value()
-		if value > 17 {
-			break
-		}
-		if value == 17 {
-			// Stand on all 17s (including soft 17 per S17 rule)
-			break
-		}
-
-		// Draw a card
-		drawn, remaining := Draw(*deck, 1)
-		if len(drawn) == 0 {
-			// Shouldn't happen with proper deck management
-			break
-		}
-		hand.Add(drawn[0])
-		*deck = remaining
+// DealerShouldHit returns true if the dealer must draw to the hand under the given rules
+// The dealer draws to 16 and stands on hard 17; soft 17 is hit under H17 and stood on under S17.
+func DealerShouldHit(hand *Hand, rules RuleSet) bool {
+	value := hand.Value()
+	if value < 17 {
+		return true
 	}
+	return value == 17 && hand.IsSoft() && rules.DealerHitsSoft17
 }
@@ -19,6 +19,15 @@ func NewDeck() []Card {
 	return deck
 }
 
+// NewDecks creates n standard 52-card decks combined into one pile
+func NewDecks(n int) []Card {
+	cards := make([]Card, 0, 52*n)
+	for i := 0; i < n; i++ {
+		cards = append(cards, NewDeck()...)
+	}
+	return cards
+}
+
 // Shuffle shuffles a deck using the provided random number generator
 func Shuffle(deck []Card, rng *rand.Rand) {
 	for i := len(deck) - 1; i > 0; i-- {
@@ -0,0 +1,99 @@
+package game
+
+// Event is something that happened in the game, published to subscribers as it happens
+// Events are delivered synchronously, in order, from inside the Game method that
+// caused them; handlers must not call back into the Game.
+type Event interface {
+	event()
+}
+
+// EventHandler receives the game's events
+type EventHandler func(Event)
+
+// CardDealt is published for every card that leaves the shoe
+type CardDealt struct {
+	Card     Card
+	Dealer   bool // Dealt to the dealer rather than a player hand
+	Hand     int  // Index of the player hand (0 for the dealer)
+	Seat     int  // Index of the seat the hand belongs to at a multi-seat table
+	FaceDown bool // The dealer's hole card; Card is only for subscribers that may see it
+}
+
+// HoleCardRevealed is published when the dealer turns over the hole card
+type HoleCardRevealed struct {
+	Card Card
+}
+
+// ActionTaken is published once a player action has been carried out
+type ActionTaken struct {
+	Hand   int
+	Action Action
+	Value  int  // Value of the hand after the action
+	Bust   bool // The action busted the hand
+}
+
+// HandSplit is published when a hand is split in two
+type HandSplit struct {
+	Hand    int // Index of the hand that was split
+	NewHand int // Index of the hand created from its second card
+}
+
+// InsuranceResolved is published when an insurance bet is settled
+type InsuranceResolved struct {
+	Hand   int
+	Bet    int
+	Won    bool
+	Payout int // Chips returned, including the stake
+}
+
+// HandResolved is published when a player hand is settled
+type HandResolved struct {
+	Hand    int
+	Outcome Outcome
+	Bet     int
+	Payout  int // Chips returned, including the stake
+}
+
+// ShoeShuffled is published when the shoe is shuffled
+type ShoeShuffled struct {
+	Emergency bool // The shoe ran out mid-round and only the discards were reshuffled
+	Refilled  bool // A stacked shoe ran out and full decks were shuffled in its place
+}
+
+// BankChanged is published for every transaction in the ledger
+type BankChanged struct {
+	Transaction
+}
+
+func (CardDealt) event()         {}
+func (HoleCardRevealed) event()  {}
+func (ActionTaken) event()       {}
+func (HandSplit) event()         {}
+func (InsuranceResolved) event() {}
+func (HandResolved) event()      {}
+func (ShoeShuffled) event()      {}
+func (BankChanged) event()       {}
+
+// Subscribe registers a handler for every event the game publishes from now on
+func (g *Game) Subscribe(handler EventHandler) {
+	g.subscribers = append(g.subscribers, handler)
+}
+
+// emit publishes an event, sharing it with the rest of the table when the game is a seat
+func (g *Game) emit(e Event) {
+	if g.table != nil {
+		g.table.publish(g, e)
+		return
+	}
+	g.deliver(e)
+}
+
+// deliver hands an event to the counter and then to each subscriber in turn
+func (g *Game) deliver(e Event) {
+	if g.Counter != nil {
+		g.Counter.Handle(e)
+	}
+	for _, handler := range g.subscribers {
+		handler(e)
+	}
+}
@@ -3,6 +3,7 @@ package game
 import (
 	"fmt"
 	"math/rand"
+	"strings"
 )
 
 // Action represents a player action
@@ -33,6 +34,41 @@ func (a Action) String() string {
 	}
 }
 
+// ContainsAction reports whether action is one of actions
+func ContainsAction(actions []Action, action Action) bool {
+	for _, a := range actions {
+		if a == action {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseAction parses an action name such as "hit" or "Double"
+func ParseAction(s string) (Action, error) {
+	for _, action := range []Action{ActionHit, ActionStand, ActionDouble, ActionSplit, ActionSurrender} {
+		if strings.EqualFold(s, action.String()) {
+			return action, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid action: %s", s)
+}
+
+// MarshalText encodes the action by name
+func (a Action) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText decodes an action name
+func (a *Action) UnmarshalText(text []byte) error {
+	action, err := ParseAction(string(text))
+	if err != nil {
+		return err
+	}
+	*a = action
+	return nil
+}
+
 // Phase represents the current phase of the game
 type Phase int
 
@@ -45,108 +81,294 @@ const (
 	PhaseGameOver
 )
 
+func (p Phase) String() string {
+	switch p {
+	case PhaseBetting:
+		return "Betting"
+	case PhaseInsurance:
+		return "Insurance"
+	case PhasePlayerAction:
+		return "PlayerAction"
+	case PhaseDealerAction:
+		return "DealerAction"
+	case PhaseResolution:
+		return "Resolution"
+	case PhaseGameOver:
+		return "GameOver"
+	default:
+		return "Unknown"
+	}
+}
+
+// MarshalText encodes the phase by name
+func (p Phase) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes a phase name
+func (p *Phase) UnmarshalText(text []byte) error {
+	for phase := PhaseBetting; phase <= PhaseGameOver; phase++ {
+		if string(text) == phase.String() {
+			*p = phase
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid phase: %s", text)
+}
+
 // Game represents the game state
 type Game struct {
+	Rules              RuleSet
 	Bank               int
-	Deck               []Card
+	Shoe               *Shoe
 	PlayerHands        []*Hand
 	DealerHand         *Hand
 	CurrentPhase       Phase
 	ActiveHandIndex    int
 	RNG                *rand.Rand
+	Seed               int64 // Seed of RNG, disclosed so sessions can be replayed
 	DealerHasBlackjack bool
 	InsuranceOffered   bool
+	PeekPending        bool // Early surrender: the dealer peeks once every spot has decided
+	HoleCardRevealed   bool
+	Counter            *Counter // Card counter, nil when counting is off
+	RoundsPlayed       int
+	Ledger             Ledger // Chips in and out of the bank this round
+
+	rngSource   *countingSource // Source of RNG, which tracks its position for saving
+	subscribers []EventHandler
+	record      *RoundRecord // Hand history of the current round
+	recordDone  bool
+	table       *Table // Table the game is a seat at, nil when playing alone
 }
 
-// NewGame creates a new game with the starting bank
-func NewGame() *Game {
+// EnableCounting starts counting every card seen with the given system
+func (g *Game) EnableCounting(system CountSystem) {
+	g.Counter = NewCounter(system, g.Rules.NumDecks)
+}
+
+// NewGameWithSeed creates a new game whose shoe is shuffled from the given seed
+// The same seed and the same player decisions always reproduce the same cards.
+func NewGameWithSeed(rules RuleSet, seed int64) *Game {
+	return NewGameWithBank(rules, seed, StartingBank)
+}
+
+// NewGameWithBank creates a game like NewGameWithSeed that starts with bank chips
+func NewGameWithBank(rules RuleSet, seed int64, bank int) *Game {
+	source := newCountingSource(seed, 0)
+	rng := rand.New(source)
 	return &Game{
-		Bank:         StartingBank,
-		RNG:          NewRand(),
+		Rules:        rules,
+		Bank:         bank,
+		Shoe:         NewShoe(rules.NumDecks, rules.Penetration, rng),
+		RNG:          rng,
+		Seed:         seed,
 		CurrentPhase: PhaseBetting,
+		Ledger:       Ledger{Opening: bank},
+		rngSource:    source,
 	}
 }
 
-// StartHand initializes a new hand with the given bet
-func (g *Game) StartHand(bet int) error {
-	if bet < MinBet {
-		return fmt.Errorf("minimum bet is %d", MinBet)
+// MaxBet returns the largest bet currently allowed by the table and the bank
+func (g *Game) MaxBet() int {
+	if g.Rules.MaxBet > 0 && g.Rules.MaxBet < g.Bank {
+		return g.Rules.MaxBet
 	}
-	if bet > g.Bank {
-		return fmt.Errorf("bet exceeds bank balance")
+	return g.Bank
+}
+
+// StartHand starts a new round with one spot for each bet, dealt in order from the first
+// Each spot is played independently and all of them are settled against the one bank.
+func (g *Game) StartHand(bets ...int) error {
+	if err := g.checkBets(bets); err != nil {
+		return err
 	}
 
-	// Create new deck and shuffle (unless one is already set for testing)
-	if len(g.Deck) == 0 {
-		g.Deck = NewDeck()
-		Shuffle(g.Deck, g.RNG)
+	// Clear the previous round into the discard tray and shuffle if the cut card came out
+	g.discardTable()
+	if g.Shoe.NeedsShuffle() {
+		g.shuffle()
 	}
 
-	// Initialize hands
-	g.PlayerHands = []*Hand{NewHand(bet)}
+	if err := g.placeBets(bets); err != nil {
+		return err
+	}
 	g.DealerHand = NewHand(0)
+
+	// Deal initial cards: each spot, dealer (hole), each spot, dealer (upcard)
+	for _, hand := range g.PlayerHands {
+		g.dealCard(hand)
+	}
+	g.dealHoleCard(g.DealerHand)
+	for _, hand := range g.PlayerHands {
+		g.dealCard(hand)
+	}
+	g.dealCard(g.DealerHand)
+	g.recordInitialDeal()
+
+	g.openRound()
+	return nil
+}
+
+// checkBets checks the bets for a round against the table limits and the bank
+func (g *Game) checkBets(bets []int) error {
+	if len(bets) == 0 {
+		return fmt.Errorf("no bet placed")
+	}
+	if len(bets) > MaxSeats {
+		return fmt.Errorf("cannot play more than %d spots", MaxSeats)
+	}
+
+	total := 0
+	for _, bet := range bets {
+		if bet < g.Rules.MinBet {
+			return fmt.Errorf("minimum bet is %d", g.Rules.MinBet)
+		}
+		if g.Rules.MaxBet > 0 && bet > g.Rules.MaxBet {
+			return fmt.Errorf("maximum bet is %d", g.Rules.MaxBet)
+		}
+		total += bet
+	}
+	if total > g.Bank {
+		return fmt.Errorf("bet exceeds bank balance")
+	}
+	return nil
+}
+
+// placeBets takes the bets for a new round and sets out an empty hand on each spot
+func (g *Game) placeBets(bets []int) error {
+	g.beginRecord(bets)
+
+	// Take the bets; a new round starts a new ledger
+	g.Ledger = Ledger{Opening: g.Bank}
+	g.PlayerHands = make([]*Hand, 0, len(bets))
+	for spot, bet := range bets {
+		hand := NewHand(bet)
+		hand.Spot = spot
+		if err := g.debit(TransactionBet, hand, bet); err != nil {
+			return err
+		}
+		g.PlayerHands = append(g.PlayerHands, hand)
+	}
+
 	g.ActiveHandIndex = 0
 	g.DealerHasBlackjack = false
 	g.InsuranceOffered = false
+	g.PeekPending = false
+	g.HoleCardRevealed = false
+	return nil
+}
 
-	// Deal initial cards: player, dealer, player, dealer
-	g.dealCard(g.PlayerHands[0])
-	g.dealCard(g.DealerHand)
-	g.dealCard(g.PlayerHands[0])
-	g.dealCard(g.DealerHand)
-
-	// Check if insurance should be offered (based on visible upcard)
-	if len(g.DealerHand.Cards) >= 2 {
-		upcard := g.DealerHand.Cards[1]
-		if upcard.IsAce() {
-			g.CurrentPhase = PhaseInsurance
-			g.InsuranceOffered = true
-		} else {
-			// Peek for blackjack if dealer shows 10
-			if g.DealerHand.Cards[0].Rank.Value() == 10 {
-				if PeekForBlackjack(g.DealerHand.Cards[0], g.DealerHand.Cards[1]) {
-					g.DealerHasBlackjack = true
-					g.CurrentPhase = PhaseResolution
-					return nil
-				}
-			}
-			g.CurrentPhase = PhasePlayerAction
+// Spots returns the number of spots being played this round
+func (g *Game) Spots() int {
+	spots := 0
+	for _, hand := range g.PlayerHands {
+		if hand.Spot >= spots {
+			spots = hand.Spot + 1
 		}
-	} else {
-		// Peek for blackjack if dealer shows 10
-		// Only check if dealer has at least one card
-		if len(g.DealerHand.Cards) > 0 && g.DealerHand.Cards[0].Rank.Value() == 10 {
-			// Only peek if dealer has 2 cards
-			if len(g.DealerHand.Cards) >= 2 {
-				if PeekForBlackjack(g.DealerHand.Cards[0], g.DealerHand.Cards[1]) {
-					g.DealerHasBlackjack = true
-					g.CurrentPhase = PhaseResolution
-					return nil
-				}
-			}
+	}
+	return spots
+}
+
+// spotHands returns the number of hands played on a spot, counting those split from it
+func (g *Game) spotHands(spot int) int {
+	n := 0
+	for _, hand := range g.PlayerHands {
+		if hand.Spot == spot {
+			n++
 		}
+	}
+	return n
+}
+
+// openRound moves a freshly dealt round to insurance, play or, after a dealer blackjack, resolution
+func (g *Game) openRound() {
+	if len(g.DealerHand.Cards) < 2 {
 		g.CurrentPhase = PhasePlayerAction
+		return
 	}
 
-	return nil
+	// Offer insurance if the dealer shows an Ace; the peek happens once it is decided
+	if g.DealerHand.Cards[1].IsAce() {
+		g.CurrentPhase = PhaseInsurance
+		g.InsuranceOffered = true
+		return
+	}
+
+	if g.peekForBlackjack() {
+		g.CurrentPhase = PhaseResolution
+		return
+	}
+	g.CurrentPhase = PhasePlayerAction
 }
 
-// TakeInsurance allows the player to take insurance with the given bet
-func (g *Game) TakeInsurance(insuranceBet int) error {
+// peekForBlackjack has the dealer check the hole card under a 10 or Ace upcard
+// Returns true if the dealer has blackjack. Under early surrender the peek is
+// deferred until every spot has had the chance to surrender.
+func (g *Game) peekForBlackjack() bool {
+	if !g.Rules.DealerPeeks {
+		return false
+	}
+
+	upcard := g.DealerHand.Cards[1]
+	if !upcard.IsAce() && upcard.Rank.Value() != 10 {
+		return false
+	}
+
+	if g.Rules.Surrender == SurrenderEarly && !g.PeekPending {
+		g.PeekPending = true
+		return false
+	}
+	g.PeekPending = false
+
+	if PeekForBlackjack(upcard, g.DealerHand.Cards[0]) {
+		g.DealerHasBlackjack = true
+		return true
+	}
+	return false
+}
+
+// TakeInsurance takes insurance with one bet for each spot, in spot order
+// A bet of 0 leaves that spot uninsured, but at least one spot must be insured.
+func (g *Game) TakeInsurance(bets ...int) error {
 	if g.CurrentPhase != PhaseInsurance {
 		return fmt.Errorf("insurance not available")
 	}
+	if len(bets) != len(g.PlayerHands) {
+		return fmt.Errorf("expected an insurance bet for each of the %d spots", len(g.PlayerHands))
+	}
 
-	maxInsurance := g.PlayerHands[0].Bet / 2
-	if insuranceBet > maxInsurance {
-		return fmt.Errorf("insurance bet cannot exceed half of original bet (%d)", maxInsurance)
+	total := 0
+	for i, insuranceBet := range bets {
+		maxInsurance := g.PlayerHands[i].Bet / 2
+		if insuranceBet > maxInsurance {
+			return fmt.Errorf("insurance bet cannot exceed half of original bet (%d)", maxInsurance)
+		}
+		if insuranceBet < 0 {
+			return fmt.Errorf("insurance bet cannot be negative")
+		}
+		total += insuranceBet
+	}
+	if total <= 0 {
+		return fmt.Errorf("insurance bet must be positive")
+	}
+	if total > g.Bank {
+		return fmt.Errorf("insufficient funds for insurance of %d", total)
 	}
 
-	g.PlayerHands[0].InsuranceBet = insuranceBet
+	for i, insuranceBet := range bets {
+		if insuranceBet == 0 {
+			continue
+		}
+		if err := g.debit(TransactionInsurance, g.PlayerHands[i], insuranceBet); err != nil {
+			return err
+		}
+		g.PlayerHands[i].InsuranceBet = insuranceBet
+	}
+	g.recordInsurance(bets)
 
 	// Peek for dealer blackjack
-	if PeekForBlackjack(g.DealerHand.Cards[0], g.DealerHand.Cards[1]) {
-		g.DealerHasBlackjack = true
+	if g.peekForBlackjack() {
 		g.CurrentPhase = PhaseResolution
 	} else {
 		g.CurrentPhase = PhasePlayerAction
@@ -160,10 +382,10 @@ func (g *Game) DeclineInsurance() error {
 	if g.CurrentPhase != PhaseInsurance {
 		return fmt.Errorf("insurance not available")
 	}
+	g.recordInsurance(nil)
 
 	// Peek for dealer blackjack
-	if PeekForBlackjack(g.DealerHand.Cards[0], g.DealerHand.Cards[1]) {
-		g.DealerHasBlackjack = true
+	if g.peekForBlackjack() {
 		g.CurrentPhase = PhaseResolution
 	} else {
 		g.CurrentPhase = PhasePlayerAction
@@ -182,22 +404,102 @@ func (g *Game) PlayerAction(action Action) error {
 		return fmt.Errorf("invalid hand index")
 	}
 
-	hand := g.PlayerHands[g.ActiveHandIndex]
+	// With a single spot the first action answers the early surrender offer
+	if g.EarlySurrenderOffered() {
+		if len(g.PlayerHands) > 1 {
+			return fmt.Errorf("every spot must answer the early surrender offer first")
+		}
+		if err := g.EarlySurrender(action == ActionSurrender); err != nil {
+			return err
+		}
+		if action == ActionSurrender || g.CurrentPhase != PhasePlayerAction {
+			return nil
+		}
+	}
+
+	handIndex := g.ActiveHandIndex
+	hand := g.PlayerHands[handIndex]
+	cardsBefore := len(hand.Cards)
 
+	var err error
 	switch action {
 	case ActionHit:
-		return g.hit(hand)
+		err = g.hit(hand)
 	case ActionStand:
-		return g.stand()
+		err = g.stand()
 	case ActionDouble:
-		return g.double(hand)
+		err = g.double(hand)
 	case ActionSplit:
-		return g.split()
+		err = g.split()
 	case ActionSurrender:
-		return g.surrender(hand)
+		err = g.surrender(hand)
 	default:
-		return fmt.Errorf("invalid action")
+		err = fmt.Errorf("invalid action")
 	}
+	if err != nil {
+		return err
+	}
+
+	g.recordAction(handIndex, action, hand, cardsBefore)
+	g.emit(ActionTaken{Hand: handIndex, Action: action, Value: hand.Value(), Bust: hand.IsBust()})
+	g.finishPlayerTurn()
+	return nil
+}
+
+// finishPlayerTurn has the dealer play once the last hand is finished; at a table, once every seat is
+func (g *Game) finishPlayerTurn() {
+	if g.CurrentPhase == PhaseDealerAction && g.table == nil {
+		g.PlayDealer()
+		g.CurrentPhase = PhaseResolution
+		g.ResolvePayouts()
+	}
+}
+
+// EarlySurrenderOffered reports whether the spots are waiting to answer the early surrender offer
+// Under early surrender the dealer doesn't peek under a 10 or an Ace until they have.
+func (g *Game) EarlySurrenderOffered() bool {
+	return g.CurrentPhase == PhasePlayerAction && g.PeekPending
+}
+
+// EarlySurrender answers the early surrender offer with one decision for each spot, in spot order
+// The chosen spots surrender and the dealer then peeks; the other spots are played
+// once the peek finds no blackjack.
+func (g *Game) EarlySurrender(surrender ...bool) error {
+	if !g.EarlySurrenderOffered() {
+		return fmt.Errorf("early surrender not offered")
+	}
+	if len(surrender) != len(g.PlayerHands) {
+		return fmt.Errorf("expected a surrender decision for each of the %d spots", len(g.PlayerHands))
+	}
+	for i, hand := range g.PlayerHands {
+		if surrender[i] && !hand.CanSurrender(g.Rules) {
+			return fmt.Errorf("spot %d cannot surrender", i+1)
+		}
+	}
+
+	for i, hand := range g.PlayerHands {
+		if !surrender[i] {
+			continue
+		}
+		hand.Surrendered = true
+		hand.IsInitialDeal = false
+		g.recordAction(i, ActionSurrender, hand, len(hand.Cards))
+		g.emit(ActionTaken{Hand: i, Action: ActionSurrender, Value: hand.Value()})
+	}
+
+	if g.peekForBlackjack() {
+		g.CurrentPhase = PhaseResolution
+		if g.table == nil {
+			g.ResolvePayouts()
+		}
+		return nil
+	}
+
+	// Play starts with the first spot that didn't surrender
+	g.ActiveHandIndex = 0
+	g.skipSurrendered()
+	g.finishPlayerTurn()
+	return nil
 }
 
 func (g *Game) hit(hand *Hand) error {
@@ -228,7 +530,7 @@ func (g *Game) stand() error {
 }
 
 func (g *Game) double(hand *Hand) error {
-	if !hand.CanDouble() {
+	if !hand.CanDouble(g.Rules) {
 		return fmt.Errorf("cannot double")
 	}
 
@@ -236,7 +538,9 @@ func (g *Game) double(hand *Hand) error {
 	if hand.Bet > g.Bank {
 		return fmt.Errorf("insufficient funds to double")
 	}
-	g.Bank -= hand.Bet // Deduct the additional bet for doubling
+	if err := g.debit(TransactionDouble, hand, hand.Bet); err != nil {
+		return err
+	}
 	hand.Bet *= 2
 	hand.Doubled = true
 	hand.IsInitialDeal = false
@@ -255,28 +559,27 @@ func (g *Game) double(hand *Hand) error {
 func (g *Game) split() error {
 	hand := g.PlayerHands[g.ActiveHandIndex]
 
-	if !hand.CanSplit() {
+	if !hand.CanSplit(g.Rules) {
 		return fmt.Errorf("cannot split")
 	}
 
-	// Cannot resplit aces
-	if hand.IsSplitAces {
-		return fmt.Errorf("cannot resplit aces")
+	// Check if the spot has reached the table's limit on hands
+	if g.spotHands(hand.Spot) >= g.Rules.MaxSplitHands {
+		return fmt.Errorf("cannot split more than %d hands", g.Rules.MaxSplitHands)
 	}
 
 	// Check if we can afford the split
 	if hand.Bet > g.Bank {
 		return fmt.Errorf("insufficient funds to split")
 	}
-	g.Bank -= hand.Bet // Deduct the bet for the new hand
 
-	// Check if we've reached the max of 4 hands
-	if len(g.PlayerHands) >= 4 {
-		return fmt.Errorf("cannot split more than 4 hands")
-	}
-
-	// Create new hand with the second card
+	// Create new hand with the second card, numbered after every hand already on the spot
 	newHand := NewHand(hand.Bet)
+	newHand.Spot = hand.Spot
+	newHand.SplitOrder = g.spotHands(hand.Spot)
+	if err := g.debit(TransactionSplit, newHand, hand.Bet); err != nil {
+		return err
+	}
 	newHand.Add(hand.Cards[1])
 
 	// Keep only the first card in the current hand
@@ -289,13 +592,14 @@ func (g *Game) split() error {
 		newHand.IsSplitAces = true
 	}
 
+	// Insert the new hand after the current hand
+	g.PlayerHands = append(g.PlayerHands[:g.ActiveHandIndex+1], append([]*Hand{newHand}, g.PlayerHands[g.ActiveHandIndex+1:]...)...)
+	g.emit(HandSplit{Hand: g.ActiveHandIndex, NewHand: g.ActiveHandIndex + 1})
+
 	// Deal one card to each hand
 	g.dealCard(hand)
 	g.dealCard(newHand)
 
-	// Insert the new hand after the current hand
-	g.PlayerHands = append(g.PlayerHands[:g.ActiveHandIndex+1], append([]*Hand{newHand}, g.PlayerHands[g.ActiveHandIndex+1:]...)...)
-
 	// Both hands now have their 2 cards, so they're in "initial" state for actions
 	// (can split again if they get a pair, can double, etc.)
 	// But they cannot have natural blackjack since they came from a split
@@ -313,7 +617,7 @@ func (g *Game) split() error {
 }
 
 func (g *Game) surrender(hand *Hand) error {
-	if !hand.CanSurrender() {
+	if !hand.CanSurrender(g.Rules) {
 		return fmt.Errorf("cannot surrender")
 	}
 
@@ -325,31 +629,37 @@ func (g *Game) surrender(hand *Hand) error {
 
 func (g *Game) advanceToNextHand() error {
 	g.ActiveHandIndex++
+	g.skipSurrendered()
+	return nil
+}
+
+// skipSurrendered moves past hands surrendered early, which have nothing left to play
+func (g *Game) skipSurrendered() {
+	for g.ActiveHandIndex < len(g.PlayerHands) && g.PlayerHands[g.ActiveHandIndex].Surrendered {
+		g.ActiveHandIndex++
+	}
 
 	if g.ActiveHandIndex >= len(g.PlayerHands) {
-		// All player hands done, move to dealer
+		// All player hands done; PlayerAction has the dealer play
 		g.CurrentPhase = PhaseDealerAction
-		g.PlayDealer()
-		g.CurrentPhase = PhaseResolution
-		g.ResolvePayouts()
 	}
-
-	return nil
 }
 
 // PlayDealer plays out the dealer's hand according to house rules
 func (g *Game) PlayDealer() {
-	// Check if all player hands are bust or surrendered
-	allBustOrSurrendered := true
+	// Check if all player hands are bust, surrendered or blackjack
+	allSettled := true
 	for _, hand := range g.PlayerHands {
-		if !hand.IsBust() && !hand.Surrendered {
-			allBustOrSurrendered = false
+		if !hand.IsBust() && !hand.Surrendered && !hand.IsBlackjack() {
+			allSettled = false
 			break
 		}
 	}
 
-	// Dealer doesn't play if all player hands are bust or surrendered
-	if allBustOrSurrendered {
+	g.revealHoleCard()
+
+	// Dealer doesn't play if every player hand is already settled
+	if allSettled {
 		return
 	}
 
@@ -358,61 +668,170 @@ func (g *Game) PlayDealer() {
 		return
 	}
 
-	DealerPlay(&g.Deck, g.DealerHand)
+	for DealerShouldHit(g.DealerHand, g.Rules) {
+		if !g.dealCard(g.DealerHand) {
+			break
+		}
+	}
 }
 
+// ResolvePayouts settles every hand against the dealer and credits the bank
 func (g *Game) ResolvePayouts() {
-	// Start with the current bank, which no longer includes the bets (already deducted)
-	finalBank := g.Bank
+	g.revealHoleCard()
 
-	for _, hand := range g.PlayerHands {
-		// Resolve insurance bet
+	// Without a peek (or with the peek still pending) the hole card is only checked now
+	if len(g.DealerHand.Cards) == 2 && g.DealerHand.IsBlackjack() {
+		g.DealerHasBlackjack = true
+	}
+	g.PeekPending = false
+
+	// Every bet was debited when it was placed, so each payout is credited in full
+	for i, hand := range g.PlayerHands {
 		if hand.InsuranceBet > 0 {
-			if g.DealerHasBlackjack {
-				// Insurance pays 2:1
-				finalBank += Payout(OutcomeWin, hand.InsuranceBet, true)
-			} else {
-				// Insurance loses
-				finalBank += Payout(OutcomeLose, hand.InsuranceBet, true)
-			}
+			payout := g.insurancePayout(hand)
+			g.credit(TransactionInsurancePayout, hand, payout)
+			g.emit(InsuranceResolved{Hand: i, Bet: hand.InsuranceBet, Won: g.DealerHasBlackjack, Payout: payout})
 		}
 
-		// If dealer has blackjack
-		if g.DealerHasBlackjack {
-			if hand.IsBlackjack() {
-				// Push - bet is returned
-				finalBank += hand.Bet
-			} else {
-				// Player loses bet (already deducted, so nothing to add back)
-			}
-			continue
+		payout := g.betPayout(hand)
+		g.credit(TransactionPayout, hand, payout)
+		g.emit(HandResolved{Hand: i, Outcome: DetermineOutcome(hand, g.DealerHand), Bet: hand.Bet, Payout: payout})
+	}
+
+	if g.Bank < g.Rules.MinBet {
+		g.CurrentPhase = PhaseGameOver
+	} else {
+		g.CurrentPhase = PhaseBetting
+	}
+
+	g.finishRecord()
+}
+
+// handPayout returns the chips credited to the bank for a hand, including its insurance
+func (g *Game) handPayout(hand *Hand) int {
+	return g.insurancePayout(hand) + g.betPayout(hand)
+}
+
+// insurancePayout returns the chips credited for the hand's insurance bet
+func (g *Game) insurancePayout(hand *Hand) int {
+	if hand.InsuranceBet == 0 {
+		return 0
+	}
+	if g.DealerHasBlackjack {
+		// Insurance pays 2:1
+		return g.Rules.Payout(OutcomeWin, hand.InsuranceBet, true)
+	}
+	return g.Rules.Payout(OutcomeLose, hand.InsuranceBet, true)
+}
+
+// betPayout returns the chips credited for the hand's main bet
+func (g *Game) betPayout(hand *Hand) int {
+	// If dealer has blackjack
+	if g.DealerHasBlackjack {
+		if hand.IsBlackjack() {
+			// Push - bet is returned
+			return hand.Bet
+		} else if hand.Surrendered {
+			// Early surrender - half the bet is returned
+			return g.Rules.Payout(OutcomeSurrender, hand.Bet, false)
 		}
+		// Otherwise the player loses the bet
+		return 0
+	}
+
+	outcome := DetermineOutcome(hand, g.DealerHand)
+	return g.Rules.Payout(outcome, hand.Bet, false)
+}
+
+// dealCard deals the next card from the shoe face up to the hand
+// Returns false only if the shoe and the discard tray are both empty.
+func (g *Game) dealCard(hand *Hand) bool {
+	return g.drawCard(hand, false)
+}
 
-		// Determine outcome
-		outcome := DetermineOutcome(hand, g.DealerHand)
-		payout := Payout(outcome, hand.Bet, false)
+// dealHoleCard deals the dealer's hole card face down; it is counted once revealed
+func (g *Game) dealHoleCard(hand *Hand) bool {
+	return g.drawCard(hand, true)
+}
 
-		// The payout function returns the total amount given to the player.
-		// Since the bet was already deducted from the bank, we add back the full payout.
-		finalBank += payout
+func (g *Game) drawCard(hand *Hand, faceDown bool) bool {
+	// Emergency reshuffle of the discards so a hand is never left short of cards;
+	// a stacked shoe due a refill is replaced by full decks instead
+	if g.Shoe.Remaining() == 0 {
+		if g.Shoe.Refill > 0 {
+			var onTable []Card
+			for _, h := range g.PlayerHands {
+				onTable = append(onTable, h.Cards...)
+			}
+			onTable = append(onTable, g.DealerHand.Cards...)
+			g.Shoe.Shuffle(onTable...)
+			g.recordReshuffle()
+			g.emit(ShoeShuffled{Refilled: true})
+		} else {
+			g.Shoe.ReshuffleDiscards()
+			g.recordReshuffle()
+			g.emit(ShoeShuffled{Emergency: true})
+		}
 	}
 
-	// Update the bank with the final calculated value
-	g.Bank = finalBank
+	card, ok := g.Shoe.Draw()
+	if !ok {
+		return false
+	}
+	hand.Add(card)
+	g.recordCard(card)
 
-	if g.Bank <= 0 {
-		g.CurrentPhase = PhaseGameOver
+	dealt := CardDealt{Card: card, FaceDown: faceDown}
+	if hand == g.DealerHand {
+		dealt.Dealer = true
 	} else {
-		g.CurrentPhase = PhaseBetting
+		dealt.Hand = g.handIndex(hand)
+		dealt.Seat = g.SeatIndex()
 	}
+	g.emit(dealt)
+	return true
 }
 
-func (g *Game) dealCard(hand *Hand) {
-	drawn, remaining := Draw(g.Deck, 1)
-	if len(drawn) > 0 {
-		hand.Add(drawn[0])
-		g.Deck = remaining
+// handIndex returns the index of a player hand
+func (g *Game) handIndex(hand *Hand) int {
+	for i, h := range g.PlayerHands {
+		if h == hand {
+			return i
+		}
 	}
+	return -1
+}
+
+// revealHoleCard turns the dealer's hole card face up
+func (g *Game) revealHoleCard() {
+	if g.table != nil {
+		g.table.revealHoleCard()
+		return
+	}
+	if g.HoleCardRevealed || g.DealerHand == nil || len(g.DealerHand.Cards) == 0 {
+		return
+	}
+	g.HoleCardRevealed = true
+	g.emit(HoleCardRevealed{Card: g.DealerHand.Cards[0]})
+}
+
+// shuffle shuffles the whole shoe, which starts the count over
+func (g *Game) shuffle() {
+	refilled := g.Shoe.Refill > 0
+	g.Shoe.Shuffle()
+	g.emit(ShoeShuffled{Refilled: refilled})
+}
+
+// discardTable moves the cards from the last round into the shoe's discard tray
+func (g *Game) discardTable() {
+	for _, hand := range g.PlayerHands {
+		g.Shoe.Discard(hand.Cards...)
+	}
+	if g.DealerHand != nil {
+		g.Shoe.Discard(g.DealerHand.Cards...)
+	}
+	g.PlayerHands = nil
+	g.DealerHand = nil
 }
 
 // GetAvailableActions returns the available actions for the current active hand
@@ -438,40 +857,46 @@ func (g *Game) GetAvailableActions() []Action {
 	}
 
 	// Split aces only get one card - no actions available except stand
-	// (handled in main loop, but this prevents any other actions)
+	// (and resplitting, where the rules allow it)
 	if hand.IsSplitAces && len(hand.Cards) >= 2 {
-		return []Action{ActionStand}
+		actions := []Action{ActionStand}
+		if g.canSplit(hand) {
+			actions = append(actions, ActionSplit)
+		}
+		return actions
 	}
 
 	actions := []Action{ActionHit, ActionStand}
 
-	// For doubling, we need enough total chips to cover the doubled bet
-	// Since the bet was already deducted in main.go, we check if g.Bank + hand.Bet >= hand.Bet * 2
-	// This simplifies to: g.Bank >= hand.Bet
-	// Example: Bank=1006, Bet=1000 -> After deduction: Bank=6, need 6>=1000? No, can't double
+	// For doubling, the additional bet must be covered by the bank
+	// The bet was debited by StartHand, so the bank must cover another bet of the same size
 	// Example: Bank=2000, Bet=1000 -> After deduction: Bank=1000, need 1000>=1000? Yes, can double
-	// However, if we want to allow doubling when total chips are sufficient, we should check:
-	// g.Bank + hand.Bet >= hand.Bet * 2, which means g.Bank >= hand.Bet
-	// But the user expects to be able to double with 1006 chips and 1000 bet, which means
-	// they want: g.Bank + hand.Bet >= hand.Bet * 2 -> 6 + 1000 >= 2000 -> 1006 >= 2000? No
-	// So the current check is correct - you need at least 2000 chips total to double a 1000 bet
-	if hand.CanDouble() && g.Bank >= hand.Bet {
+	if hand.CanDouble(g.Rules) && g.Bank >= hand.Bet {
 		actions = append(actions, ActionDouble)
 	}
 
 	// For splitting, we need enough remaining bank to cover the bet for the new hand
-	// Since the bet was already deducted, we check if g.Bank >= hand.Bet
-	if hand.CanSplit() && g.Bank >= hand.Bet && len(g.PlayerHands) < 4 {
+	if g.canSplit(hand) {
 		actions = append(actions, ActionSplit)
 	}
 
-	if hand.CanSurrender() {
+	if hand.CanSurrender(g.Rules) {
 		actions = append(actions, ActionSurrender)
 	}
 
 	return actions
 }
 
+// canSplit reports whether the hand may be split right now, including the hand limit and bank
+func (g *Game) canSplit(hand *Hand) bool {
+	return hand.CanSplit(g.Rules) && g.Bank >= hand.Bet && g.spotHands(hand.Spot) < g.Rules.MaxSplitHands
+}
+
+// DealerUpcard returns the dealer's face-up card
+func (g *Game) DealerUpcard() Card {
+	return g.DealerHand.Cards[1]
+}
+
 // GetCurrentHand returns the current active hand
 func (g *Game) GetCurrentHand() *Hand {
 	if g.ActiveHandIndex >= len(g.PlayerHands) {
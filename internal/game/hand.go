@@ -2,14 +2,16 @@ package game
 
 // Hand represents a blackjack hand
 type Hand struct {
-	Cards         []Card
-	Bet           int
-	IsSplitAces   bool
-	Doubled       bool
-	Surrendered   bool
-	IsInitialDeal bool // True if this hand has had no actions yet
-	IsFromSplit   bool // True if this hand came from a split (cannot have natural blackjack)
-	InsuranceBet  int
+	Cards         []Card `json:"cards"`
+	Bet           int    `json:"bet"`
+	IsSplitAces   bool   `json:"split_aces,omitempty"`
+	Doubled       bool   `json:"doubled,omitempty"`
+	Surrendered   bool   `json:"surrendered,omitempty"`
+	IsInitialDeal bool   `json:"initial_deal,omitempty"` // True if this hand has had no actions yet
+	IsFromSplit   bool   `json:"from_split,omitempty"`   // True if this hand came from a split (cannot have natural blackjack)
+	InsuranceBet  int    `json:"insurance_bet,omitempty"`
+	Spot          int    `json:"spot,omitempty"`        // Index of the spot the hand is played on
+	SplitOrder    int    `json:"split_order,omitempty"` // Order the hand was split off its spot in, 0 for the hand first dealt there
 }
 
 // NewHand creates a new hand with the given bet
@@ -76,28 +78,54 @@ func (h *Hand) IsSoft() bool {
 	return isSoft
 }
 
-// CanSplit returns true if the hand can be split
-func (h *Hand) CanSplit() bool {
+// CanSplit returns true if the hand can be split under the given rules
+func (h *Hand) CanSplit(rules RuleSet) bool {
 	// Can split on initial deal (or after a split) with exactly 2 cards of the same rank
 	// Cannot split after hitting (IsInitialDeal becomes false after first action)
-	// Special case: split aces cannot be resplit (handled in game logic)
-	return h.IsInitialDeal && len(h.Cards) == 2 && h.Cards[0].Rank == h.Cards[1].Rank
+	// The table-wide limit on the number of hands is enforced by the game
+	if !h.IsInitialDeal || len(h.Cards) != 2 || h.Cards[0].Rank != h.Cards[1].Rank {
+		return false
+	}
+	if rules.MaxSplitHands < 2 {
+		return false
+	}
+	// Split aces can only be resplit when the table allows RSA
+	if h.IsSplitAces && !rules.ResplitAces {
+		return false
+	}
+	return true
 }
 
-// CanDouble returns true if the hand can be doubled
-func (h *Hand) CanDouble() bool {
+// CanDouble returns true if the hand can be doubled under the given rules
+func (h *Hand) CanDouble(rules RuleSet) bool {
 	// Can only double on first action
 	// Cannot double on split aces (they only get one card)
-	if h.IsSplitAces {
+	if h.IsSplitAces || !h.IsInitialDeal || len(h.Cards) != 2 {
 		return false
 	}
-	return h.IsInitialDeal
+	if h.IsFromSplit && !rules.DoubleAfterSplit {
+		return false
+	}
+
+	switch rules.DoubleRule {
+	case Double9To11:
+		value := h.Value()
+		return value >= 9 && value <= 11
+	case Double10To11:
+		value := h.Value()
+		return value >= 10 && value <= 11
+	default:
+		return true
+	}
 }
 
-// CanSurrender returns true if the hand can surrender (late surrender only)
-func (h *Hand) CanSurrender() bool {
-	// Can only surrender on first action
-	return h.IsInitialDeal && len(h.Cards) == 2
+// CanSurrender returns true if the hand can surrender under the given rules
+func (h *Hand) CanSurrender(rules RuleSet) bool {
+	if rules.Surrender == SurrenderNone {
+		return false
+	}
+	// Can only surrender the original two-card hand, never after a split
+	return h.IsInitialDeal && len(h.Cards) == 2 && !h.IsFromSplit
 }
 
 // String returns a string representation of the hand
@@ -0,0 +1,220 @@
+package game
+
+// RoundRecord is the complete record of a single round, suitable for a hand history
+// The engine builds it as the round is played; LastRound returns it once the round
+// has been resolved.
+type RoundRecord struct {
+	Round       int              `json:"round"`
+	Seed        int64            `json:"seed"`
+	Rules       RuleSet          `json:"rules"`
+	Shoe        ShoePosition     `json:"shoe"`
+	BankBefore  int              `json:"bank_before"`
+	Bet         int              `json:"bet"`             // Total of the bets on every spot
+	Spots       []int            `json:"spots,omitempty"` // Bet on each spot when more than one was played
+	InitialDeal DealRecord       `json:"initial_deal"`
+	Insurance   *InsuranceRecord `json:"insurance,omitempty"`
+	Actions     []ActionRecord   `json:"actions"`
+	DealerDraws []Card           `json:"dealer_draws"`
+	Dealer      DealerRecord     `json:"dealer"`
+	Hands       []HandResult     `json:"hands"`
+	BankAfter   int              `json:"bank_after"`
+	Ledger      []Transaction    `json:"ledger"`
+	Cards       []Card           `json:"cards"`                // Every card in the order it left the shoe
+	Reshuffled  bool             `json:"reshuffled,omitempty"` // The shoe was reshuffled or refilled mid-round
+	Seat        int              `json:"seat,omitempty"`       // Seat number from 1 at a multi-seat table
+}
+
+// ShoePosition records where in the shoe a round started
+type ShoePosition struct {
+	Shuffle   int `json:"shuffle"`   // Number of shuffles so far (1 for the first shoe)
+	Dealt     int `json:"dealt"`     // Cards in the discard tray before the round
+	Remaining int `json:"remaining"` // Undealt cards before the round
+	CutCard   int `json:"cut_card"`  // Undealt cards left when the cut card comes out
+}
+
+// DealRecord records the initial two-card deal
+type DealRecord struct {
+	Player       []Card `json:"player"`
+	DealerUpcard Card   `json:"dealer_upcard"`
+	DealerHole   Card   `json:"dealer_hole"`
+	// Spots holds the cards dealt to each spot when more than one was played
+	Spots [][]Card `json:"spots,omitempty"`
+}
+
+// InsuranceRecord records the insurance decision when it was offered
+type InsuranceRecord struct {
+	Taken bool  `json:"taken"`
+	Bet   int   `json:"bet"`             // Total insurance across every spot
+	Spots []int `json:"spots,omitempty"` // Insurance on each spot when more than one was played
+}
+
+// ActionRecord records one player action and the cards it drew
+type ActionRecord struct {
+	Hand   int    `json:"hand"` // Index of the hand acted on
+	Action Action `json:"action"`
+	Cards  []Card `json:"cards,omitempty"`
+	// NewHand is the index of the hand created by a split
+	NewHand int `json:"new_hand,omitempty"`
+}
+
+// DealerRecord records the dealer's final hand
+type DealerRecord struct {
+	Cards     []Card `json:"cards"`
+	Value     int    `json:"value"`
+	Blackjack bool   `json:"blackjack"`
+	Bust      bool   `json:"bust"`
+}
+
+// HandResult records how a player hand finished and what it paid
+type HandResult struct {
+	Spot        int     `json:"spot,omitempty"`
+	Cards       []Card  `json:"cards"`
+	Bet         int     `json:"bet"`
+	Value       int     `json:"value"`
+	Doubled     bool    `json:"doubled,omitempty"`
+	Surrendered bool    `json:"surrendered,omitempty"`
+	FromSplit   bool    `json:"from_split,omitempty"`
+	Insurance   int     `json:"insurance,omitempty"`
+	Outcome     Outcome `json:"outcome"`
+	Payout      int     `json:"payout"` // Chips returned to the bank for this hand and its insurance
+}
+
+// LastRound returns the record of the most recent resolved round, or nil if there is none
+func (g *Game) LastRound() *RoundRecord {
+	if g.record == nil || !g.recordDone {
+		return nil
+	}
+	return g.record
+}
+
+// beginRecord starts recording a new round before any cards are dealt
+func (g *Game) beginRecord(bets []int) {
+	bet := 0
+	for _, b := range bets {
+		bet += b
+	}
+
+	g.RoundsPlayed++
+	g.record = &RoundRecord{
+		Round:      g.RoundsPlayed,
+		Seed:       g.Seed,
+		Rules:      g.Rules,
+		BankBefore: g.Bank,
+		Bet:        bet,
+		Shoe: ShoePosition{
+			Shuffle:   g.Shoe.Shuffles,
+			Dealt:     len(g.Shoe.Discards),
+			Remaining: g.Shoe.Remaining(),
+			CutCard:   g.Shoe.CutCard,
+		},
+		Actions:     []ActionRecord{},
+		DealerDraws: []Card{},
+	}
+	if len(bets) > 1 {
+		g.record.Spots = append([]int(nil), bets...)
+	}
+	g.recordDone = false
+}
+
+// recordInitialDeal records the first four cards once they are on the table
+func (g *Game) recordInitialDeal() {
+	if g.record == nil || len(g.DealerHand.Cards) < 2 {
+		return
+	}
+	g.record.InitialDeal = DealRecord{
+		Player:       append([]Card(nil), g.PlayerHands[0].Cards...),
+		DealerUpcard: g.DealerHand.Cards[1],
+		DealerHole:   g.DealerHand.Cards[0],
+	}
+	if len(g.PlayerHands) > 1 {
+		for _, hand := range g.PlayerHands {
+			g.record.InitialDeal.Spots = append(g.record.InitialDeal.Spots, append([]Card(nil), hand.Cards...))
+		}
+	}
+}
+
+// recordCard records a card leaving the shoe
+func (g *Game) recordCard(card Card) {
+	if g.record == nil || g.recordDone {
+		return
+	}
+	g.record.Cards = append(g.record.Cards, card)
+}
+
+// recordReshuffle records that the shoe was reshuffled or refilled mid-round
+func (g *Game) recordReshuffle() {
+	if g.record != nil && !g.recordDone {
+		g.record.Reshuffled = true
+	}
+}
+
+// recordInsurance records the insurance decision, given as the bet on each spot
+func (g *Game) recordInsurance(bets []int) {
+	if g.record == nil {
+		return
+	}
+	bet := 0
+	for _, b := range bets {
+		bet += b
+	}
+	g.record.Insurance = &InsuranceRecord{Taken: bet > 0, Bet: bet}
+	if len(bets) > 1 {
+		g.record.Insurance.Spots = append([]int(nil), bets...)
+	}
+}
+
+// recordAction records a player action on the hand along with the cards it drew
+// cardsBefore is the number of cards the hand held before the action.
+func (g *Game) recordAction(handIndex int, action Action, hand *Hand, cardsBefore int) {
+	if g.record == nil {
+		return
+	}
+
+	rec := ActionRecord{Hand: handIndex, Action: action}
+	if action == ActionSplit {
+		// Each half of the split received one new card
+		newHand := g.PlayerHands[handIndex+1]
+		rec.Cards = []Card{hand.Cards[1], newHand.Cards[1]}
+		rec.NewHand = handIndex + 1
+	} else if len(hand.Cards) > cardsBefore {
+		rec.Cards = append(rec.Cards, hand.Cards[cardsBefore:]...)
+	}
+	g.record.Actions = append(g.record.Actions, rec)
+}
+
+// finishRecord records the dealer's hand and every hand's outcome once payouts are settled
+func (g *Game) finishRecord() {
+	if g.record == nil || g.recordDone {
+		return
+	}
+
+	rec := g.record
+	if len(g.DealerHand.Cards) > 2 {
+		rec.DealerDraws = append(rec.DealerDraws, g.DealerHand.Cards[2:]...)
+	}
+	rec.Dealer = DealerRecord{
+		Cards:     append([]Card(nil), g.DealerHand.Cards...),
+		Value:     g.DealerHand.Value(),
+		Blackjack: g.DealerHasBlackjack,
+		Bust:      g.DealerHand.IsBust(),
+	}
+
+	rec.Hands = make([]HandResult, 0, len(g.PlayerHands))
+	for _, hand := range g.PlayerHands {
+		rec.Hands = append(rec.Hands, HandResult{
+			Spot:        hand.Spot,
+			Cards:       append([]Card(nil), hand.Cards...),
+			Bet:         hand.Bet,
+			Value:       hand.Value(),
+			Doubled:     hand.Doubled,
+			Surrendered: hand.Surrendered,
+			FromSplit:   hand.IsFromSplit,
+			Insurance:   hand.InsuranceBet,
+			Outcome:     DetermineOutcome(hand, g.DealerHand),
+			Payout:      g.handPayout(hand),
+		})
+	}
+	rec.BankAfter = g.Bank
+	rec.Ledger = append([]Transaction(nil), g.Ledger.Transactions...)
+	g.recordDone = true
+}
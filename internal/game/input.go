@@ -2,36 +2,83 @@ package game
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
 )
 
-// PromptBet prompts the user for a bet amount
-func PromptBet(reader io.Reader, bank int) (int, error) {
-	scanner := bufio.NewScanner(reader)
+// ErrToggleCount is returned by PromptBet when the player asks to show or hide the count
+var ErrToggleCount = errors.New("toggle count display")
 
+// InputSource reads the player's answers and writes the prompts asking for them
+// A session shares one source between all of its prompts. Input is read through a
+// single buffer, so answers typed ahead or piped in are kept for the prompts that
+// follow instead of being lost with a prompt's own reader.
+type InputSource struct {
+	scanner *bufio.Scanner
+	out     io.Writer
+}
+
+// NewInputSource creates a source reading answers from r and writing prompts to w
+func NewInputSource(r io.Reader, w io.Writer) *InputSource {
+	return &InputSource{scanner: bufio.NewScanner(r), out: w}
+}
+
+// ReadLine returns the next line of input, or an error once there is none left
+func (in *InputSource) ReadLine() (string, error) {
+	if !in.scanner.Scan() {
+		if err := in.scanner.Err(); err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+		return "", fmt.Errorf("failed to read input: %w", io.EOF)
+	}
+	return in.scanner.Text(), nil
+}
+
+// Printf writes to the source's output, where its prompts go
+func (in *InputSource) Printf(format string, args ...any) {
+	fmt.Fprintf(in.out, format, args...)
+}
+
+// Println writes a line to the source's output
+func (in *InputSource) Println(args ...any) {
+	fmt.Fprintln(in.out, args...)
+}
+
+// PromptBet prompts the user for a bet amount between minBet and maxBet
+// Entering "c" returns ErrToggleCount so the caller can show or hide the count, and
+// "q" returns ErrQuit to leave the table.
+func (in *InputSource) PromptBet(minBet int, maxBet int) (int, error) {
 	for {
-		fmt.Printf("Enter bet (1-%d): ", bank)
-		if !scanner.Scan() {
-			return 0, fmt.Errorf("failed to read input")
+		in.Printf("Enter bet (%d-%d, q to leave): ", minBet, maxBet)
+		line, err := in.ReadLine()
+		if err != nil {
+			return 0, err
+		}
+
+		input := strings.TrimSpace(line)
+		if strings.EqualFold(input, "c") {
+			return 0, ErrToggleCount
+		}
+		if strings.EqualFold(input, "q") {
+			return 0, ErrQuit
 		}
 
-		input := strings.TrimSpace(scanner.Text())
 		bet, err := strconv.Atoi(input)
 		if err != nil {
-			fmt.Println("Invalid input. Please enter a number.")
+			in.Println("Invalid input. Please enter a number.")
 			continue
 		}
 
-		if bet < MinBet {
-			fmt.Printf("Minimum bet is %d.\n", MinBet)
+		if bet < minBet {
+			in.Printf("Minimum bet is %d.\n", minBet)
 			continue
 		}
 
-		if bet > bank {
-			fmt.Printf("Bet exceeds bank balance (%d).\n", bank)
+		if bet > maxBet {
+			in.Printf("Maximum bet is %d.\n", maxBet)
 			continue
 		}
 
@@ -40,16 +87,20 @@ func PromptBet(reader io.Reader, bank int) (int, error) {
 }
 
 // PromptAction prompts the user for an action
-func PromptAction(reader io.Reader, actions []Action, handNum int, totalHands int) (Action, error) {
-	scanner := bufio.NewScanner(reader)
-
+// If hint is not nil, entering "?" prints the hint and prompts again.
+func (in *InputSource) PromptAction(actions []Action, handNum int, totalHands int, hint func() string) (Action, error) {
 	for {
-		fmt.Print(RenderAvailableActions(actions, handNum, totalHands) + ": ")
-		if !scanner.Scan() {
-			return 0, fmt.Errorf("failed to read input")
+		prompt := RenderAvailableActions(actions, handNum, totalHands)
+		if hint != nil {
+			prompt += ", (?) hint"
+		}
+		in.Printf("%s: ", prompt)
+		line, err := in.ReadLine()
+		if err != nil {
+			return 0, err
 		}
 
-		input := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		input := strings.ToLower(strings.TrimSpace(line))
 
 		// Parse action
 		var action Action
@@ -58,26 +109,33 @@ func PromptAction(reader io.Reader, actions []Action, handNum int, totalHands in
 		switch input {
 		case "h", "hit":
 			action = ActionHit
-			valid = containsAction(actions, ActionHit)
+			valid = ContainsAction(actions, ActionHit)
 		case "s", "stand":
 			action = ActionStand
-			valid = containsAction(actions, ActionStand)
+			valid = ContainsAction(actions, ActionStand)
 		case "d", "double":
 			action = ActionDouble
-			valid = containsAction(actions, ActionDouble)
+			valid = ContainsAction(actions, ActionDouble)
 		case "p", "split":
 			action = ActionSplit
-			valid = containsAction(actions, ActionSplit)
+			valid = ContainsAction(actions, ActionSplit)
 		case "r", "surrender":
 			action = ActionSurrender
-			valid = containsAction(actions, ActionSurrender)
+			valid = ContainsAction(actions, ActionSurrender)
+		case "?":
+			if hint == nil {
+				in.Println("No hint available.")
+			} else {
+				in.Println("💡 " + hint())
+			}
+			continue
 		default:
-			fmt.Println("Invalid action. Please try again.")
+			in.Println("Invalid action. Please try again.")
 			continue
 		}
 
 		if !valid {
-			fmt.Println("Action not available. Please choose from available actions.")
+			in.Println("Action not available. Please choose from available actions.")
 			continue
 		}
 
@@ -86,16 +144,15 @@ func PromptAction(reader io.Reader, actions []Action, handNum int, totalHands in
 }
 
 // PromptYesNo prompts the user for a yes/no answer
-func PromptYesNo(reader io.Reader, prompt string) (bool, error) {
-	scanner := bufio.NewScanner(reader)
-
+func (in *InputSource) PromptYesNo(prompt string) (bool, error) {
 	for {
-		fmt.Print(prompt + " (y/n): ")
-		if !scanner.Scan() {
-			return false, fmt.Errorf("failed to read input")
+		in.Printf("%s (y/n): ", prompt)
+		line, err := in.ReadLine()
+		if err != nil {
+			return false, err
 		}
 
-		input := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		input := strings.ToLower(strings.TrimSpace(line))
 
 		switch input {
 		case "y", "yes":
@@ -103,48 +160,38 @@ func PromptYesNo(reader io.Reader, prompt string) (bool, error) {
 		case "n", "no":
 			return false, nil
 		default:
-			fmt.Println("Invalid input. Please enter 'y' or 'n'.")
+			in.Println("Invalid input. Please enter 'y' or 'n'.")
 			continue
 		}
 	}
 }
 
 // PromptInsurance prompts the user for an insurance bet
-func PromptInsurance(reader io.Reader, maxInsurance int) (int, error) {
-	scanner := bufio.NewScanner(reader)
-
+func (in *InputSource) PromptInsurance(maxInsurance int) (int, error) {
 	for {
-		fmt.Printf("Insurance bet (0-%d): ", maxInsurance)
-		if !scanner.Scan() {
-			return 0, fmt.Errorf("failed to read input")
+		in.Printf("Insurance bet (0-%d): ", maxInsurance)
+		line, err := in.ReadLine()
+		if err != nil {
+			return 0, err
 		}
 
-		input := strings.TrimSpace(scanner.Text())
+		input := strings.TrimSpace(line)
 		bet, err := strconv.Atoi(input)
 		if err != nil {
-			fmt.Println("Invalid input. Please enter a number.")
+			in.Println("Invalid input. Please enter a number.")
 			continue
 		}
 
 		if bet < 0 {
-			fmt.Println("Insurance bet cannot be negative.")
+			in.Println("Insurance bet cannot be negative.")
 			continue
 		}
 
 		if bet > maxInsurance {
-			fmt.Printf("Insurance bet cannot exceed %d.\n", maxInsurance)
+			in.Printf("Insurance bet cannot exceed %d.\n", maxInsurance)
 			continue
 		}
 
 		return bet, nil
 	}
 }
-
-func containsAction(actions []Action, action Action) bool {
-	for _, a := range actions {
-		if a == action {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,156 @@
+package game
+
+import "fmt"
+
+// TransactionKind identifies why chips moved in or out of the bank
+type TransactionKind int
+
+const (
+	TransactionBet TransactionKind = iota
+	TransactionDouble
+	TransactionSplit
+	TransactionInsurance
+	TransactionPayout
+	TransactionInsurancePayout
+)
+
+func (k TransactionKind) String() string {
+	switch k {
+	case TransactionBet:
+		return "bet"
+	case TransactionDouble:
+		return "double"
+	case TransactionSplit:
+		return "split"
+	case TransactionInsurance:
+		return "insurance"
+	case TransactionPayout:
+		return "payout"
+	case TransactionInsurancePayout:
+		return "insurance_payout"
+	default:
+		return "unknown"
+	}
+}
+
+// MarshalText encodes the kind by name
+func (k TransactionKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText decodes a kind name
+func (k *TransactionKind) UnmarshalText(text []byte) error {
+	for kind := TransactionBet; kind <= TransactionInsurancePayout; kind++ {
+		if string(text) == kind.String() {
+			*k = kind
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid transaction kind: %s", text)
+}
+
+// Transaction is a single movement of chips in or out of the bank
+// The hand the chips belong to is named by its spot and split order, which don't change
+// when later splits move the hands around.
+type Transaction struct {
+	Kind       TransactionKind `json:"kind"`
+	Spot       int             `json:"spot"`
+	SplitOrder int             `json:"split_order,omitempty"`
+	Amount     int             `json:"amount"`  // Positive for credits, negative for debits
+	Balance    int             `json:"balance"` // Bank after the transaction
+}
+
+// Ledger records every transaction of the current round
+// Each round starts a new ledger whose opening balance is the bank before the bet.
+type Ledger struct {
+	Opening      int           `json:"opening"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+// Balance returns the opening balance plus every transaction
+func (l Ledger) Balance() int {
+	balance := l.Opening
+	for _, tx := range l.Transactions {
+		balance += tx.Amount
+	}
+	return balance
+}
+
+// CheckBank verifies the ledger against the bank and the bets on the table
+// Every transaction must leave the balance it records, the ledger must balance to the bank,
+// and the chips debited for each hand must add up to its bet and insurance.
+func (g *Game) CheckBank() error {
+	balance := g.Ledger.Opening
+	type handKey struct{ spot, splitOrder int }
+	bets := make(map[handKey]int)
+	insurance := make(map[handKey]int)
+	for i, tx := range g.Ledger.Transactions {
+		balance += tx.Amount
+		if tx.Balance != balance {
+			return fmt.Errorf("transaction %d (%s, %+d) records a balance of %d, expected %d",
+				i+1, tx.Kind, tx.Amount, tx.Balance, balance)
+		}
+		key := handKey{tx.Spot, tx.SplitOrder}
+		switch tx.Kind {
+		case TransactionBet, TransactionDouble, TransactionSplit:
+			bets[key] -= tx.Amount
+		case TransactionInsurance:
+			insurance[key] -= tx.Amount
+		}
+	}
+	if balance != g.Bank {
+		return fmt.Errorf("bank is %d chips but the ledger balances to %d", g.Bank, balance)
+	}
+
+	// The hands are cleared away before the next round's ledger is opened
+	if len(g.PlayerHands) == 0 {
+		return nil
+	}
+	for _, hand := range g.PlayerHands {
+		key := handKey{hand.Spot, hand.SplitOrder}
+		if bets[key] != hand.Bet {
+			return fmt.Errorf("spot %d hand %d bets %d chips but the ledger took %d for it",
+				hand.Spot+1, hand.SplitOrder+1, hand.Bet, bets[key])
+		}
+		if insurance[key] != hand.InsuranceBet {
+			return fmt.Errorf("spot %d hand %d has %d chips of insurance but the ledger took %d for it",
+				hand.Spot+1, hand.SplitOrder+1, hand.InsuranceBet, insurance[key])
+		}
+		delete(bets, key)
+		delete(insurance, key)
+	}
+	for key, amount := range bets {
+		return fmt.Errorf("the ledger took %d chips for spot %d hand %d, which isn't on the table",
+			amount, key.spot+1, key.splitOrder+1)
+	}
+	for key, amount := range insurance {
+		return fmt.Errorf("the ledger took %d chips of insurance for spot %d hand %d, which isn't on the table",
+			amount, key.spot+1, key.splitOrder+1)
+	}
+	return nil
+}
+
+// debit takes chips for a hand from the bank, failing without any change if the bank is short
+func (g *Game) debit(kind TransactionKind, hand *Hand, amount int) error {
+	if amount > g.Bank {
+		return fmt.Errorf("insufficient funds for %s of %d", kind, amount)
+	}
+	g.Bank -= amount
+	g.post(Transaction{kind, hand.Spot, hand.SplitOrder, -amount, g.Bank})
+	return nil
+}
+
+// credit adds chips for a hand to the bank; nothing is recorded for a zero amount
+func (g *Game) credit(kind TransactionKind, hand *Hand, amount int) {
+	if amount == 0 {
+		return
+	}
+	g.Bank += amount
+	g.post(Transaction{kind, hand.Spot, hand.SplitOrder, amount, g.Bank})
+}
+
+// post adds a transaction to the ledger and publishes it
+func (g *Game) post(tx Transaction) {
+	g.Ledger.Transactions = append(g.Ledger.Transactions, tx)
+	g.emit(BankChanged{tx})
+}
@@ -0,0 +1,85 @@
+package game
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// playSettleEverything plays the scenario that splits, doubles, insures and surrenders in one round
+func playSettleEverything(t *testing.T) *Game {
+	t.Helper()
+	s, err := loadScenario(filepath.Join("testdata", "settle_everything.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := NewGameWithSeed(s.rules, FixedSeed)
+	g.Shoe = NewStackedShoe(s.cards, g.RNG)
+	if err := g.PlayRound(&scriptedPlayer{s: s}); err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
+
+// TestLedgerNamesHandsBySpot checks that a split doesn't change the hand the chips are recorded for
+// Spot 1's split moves spot 2's hand from index 1 to index 2 on the table.
+func TestLedgerNamesHandsBySpot(t *testing.T) {
+	g := playSettleEverything(t)
+
+	type entry struct {
+		kind       TransactionKind
+		spot       int
+		splitOrder int
+		amount     int
+	}
+	want := []entry{
+		{TransactionBet, 0, 0, -10},
+		{TransactionBet, 1, 0, -10},
+		{TransactionInsurance, 0, 0, -5},
+		{TransactionSplit, 0, 1, -10},
+		{TransactionDouble, 0, 0, -10},
+		{TransactionPayout, 0, 0, 40},
+		{TransactionPayout, 0, 1, 20},
+		{TransactionPayout, 1, 0, 5},
+	}
+	var got []entry
+	for _, tx := range g.Ledger.Transactions {
+		got = append(got, entry{tx.Kind, tx.Spot, tx.SplitOrder, tx.Amount})
+	}
+	if len(got) != len(want) {
+		t.Fatalf("ledger is %v, expected %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("transaction %d is %v, expected %v", i+1, got[i], want[i])
+		}
+	}
+	if err := g.CheckBank(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestCheckBankFindsMismatches breaks the bank, the ledger or the bets and expects CheckBank to notice
+func TestCheckBankFindsMismatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(g *Game)
+		want   string
+	}{
+		{"bank", func(g *Game) { g.Bank++ }, "ledger balances to"},
+		{"running balance", func(g *Game) { g.Ledger.Transactions[3].Balance-- }, "transaction 4 (split, -10) records a balance"},
+		{"bet", func(g *Game) { g.PlayerHands[1].Bet += 10 }, "spot 1 hand 2 bets 20 chips but the ledger took 10"},
+		{"insurance", func(g *Game) { g.PlayerHands[0].InsuranceBet = 0 }, "spot 1 hand 1 has 0 chips of insurance but the ledger took 5"},
+		{"missing hand", func(g *Game) { g.PlayerHands = g.PlayerHands[:2] }, "for spot 2 hand 1, which isn't on the table"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := playSettleEverything(t)
+			tt.tamper(g)
+			err := g.CheckBank()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("expected an error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrQuit is returned by a Player that wants to leave the table
+var ErrQuit = errors.New("player left the table")
+
+// Player makes the decisions for one seat: how much to bet, whether to insure and how to play
+// The game is passed in so a player can look at the table, but players must not change it.
+type Player interface {
+	// Bet returns the bet for the next round, or ErrQuit to stop playing
+	Bet(g *Game) (int, error)
+	// Insurance returns the insurance bet, up to max, for the active hand when the dealer
+	// shows an Ace; 0 declines. With several spots it is asked once for each spot.
+	Insurance(g *Game, max int) (int, error)
+	// Act chooses one of the available actions for the active hand
+	Act(g *Game, hand *Hand, available []Action) (Action, error)
+}
+
+// SpotBettor is a Player that plays several spots a round
+// PlayRound asks it for its bets instead of calling Bet.
+type SpotBettor interface {
+	// Bets returns one bet for each spot to play next round, or ErrQuit to stop playing
+	Bets(g *Game) ([]int, error)
+}
+
+// PlayRound plays one round to the end, asking the player for every decision
+// If a round is already in progress (for example one restored from a save) it is
+// played from where it stopped. Hands with no decision to make, such as a 21, are
+// stood automatically. An error from the player stops the round where it is.
+func (g *Game) PlayRound(p Player) error {
+	switch g.CurrentPhase {
+	case PhaseGameOver:
+		return fmt.Errorf("game over")
+	case PhaseBetting:
+		bets, err := askBets(g, p)
+		if err != nil {
+			return err
+		}
+		if err := g.StartHand(bets...); err != nil {
+			return err
+		}
+	}
+
+	if g.CurrentPhase == PhaseInsurance {
+		if err := g.offerInsurance(p); err != nil {
+			return err
+		}
+	}
+
+	if g.CurrentPhase == PhaseResolution {
+		g.ResolvePayouts()
+	}
+
+	var chosen map[*Hand]Action
+	if g.EarlySurrenderOffered() {
+		var err error
+		if chosen, err = g.offerSurrender(p); err != nil {
+			return err
+		}
+	}
+
+	return g.playHands(p, chosen)
+}
+
+// playHands asks the player how to play each hand until none are left to play
+// A hand with an action already chosen, while the early surrender offer was open, plays it
+// without asking again.
+func (g *Game) playHands(p Player, chosen map[*Hand]Action) error {
+	for g.CurrentPhase == PhasePlayerAction {
+		hand := g.GetCurrentHand()
+		available := g.GetAvailableActions()
+
+		action, ok := chosen[hand]
+		switch {
+		case ok:
+			delete(chosen, hand)
+		case len(available) > 0:
+			var err error
+			action, err = p.Act(g, hand, available)
+			if err != nil {
+				return err
+			}
+		default:
+			action = ActionStand
+		}
+		if err := g.PlayerAction(action); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// askBets asks the player for the next round's bets, one for each spot
+func askBets(g *Game, p Player) ([]int, error) {
+	if sb, ok := p.(SpotBettor); ok {
+		return sb.Bets(g)
+	}
+	bet, err := p.Bet(g)
+	if err != nil {
+		return nil, err
+	}
+	return []int{bet}, nil
+}
+
+// offerInsurance asks the player about insurance on each spot in turn
+// A spot is skipped when the bank can't cover any insurance on it.
+func (g *Game) offerInsurance(p Player) error {
+	defer func() { g.ActiveHandIndex = 0 }()
+
+	bets := make([]int, len(g.PlayerHands))
+	available := g.Bank
+	taken := false
+	for i, hand := range g.PlayerHands {
+		max := hand.Bet / 2
+		if max > available {
+			max = available
+		}
+		if max == 0 {
+			continue
+		}
+
+		g.ActiveHandIndex = i
+		bet, err := p.Insurance(g, max)
+		if err != nil {
+			return err
+		}
+		bets[i] = bet
+		available -= bet
+		taken = taken || bet > 0
+	}
+
+	if taken {
+		return g.TakeInsurance(bets...)
+	}
+	return g.DeclineInsurance()
+}
+
+// offerSurrender asks the player for each spot's first decision before the dealer peeks
+// Spots that choose to surrender do so at once. The actions chosen for the other spots
+// are returned, by hand, to be played once the peek finds no blackjack.
+func (g *Game) offerSurrender(p Player) (map[*Hand]Action, error) {
+	surrender := make([]bool, len(g.PlayerHands))
+	chosen := make(map[*Hand]Action)
+	for i, hand := range g.PlayerHands {
+		g.ActiveHandIndex = i
+		available := g.GetAvailableActions()
+		if len(available) == 0 {
+			continue
+		}
+
+		action, err := p.Act(g, hand, available)
+		if err != nil {
+			g.ActiveHandIndex = 0
+			return nil, err
+		}
+		if action == ActionSurrender {
+			surrender[i] = true
+		} else {
+			chosen[hand] = action
+		}
+	}
+
+	g.ActiveHandIndex = 0
+	return chosen, g.EarlySurrender(surrender...)
+}
@@ -0,0 +1,59 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// spotHand builds a hand on a spot from the given cards
+func spotHand(spot int, cards ...Card) *Hand {
+	h := NewHand(10)
+	h.Spot = spot
+	for _, card := range cards {
+		h.Add(card)
+	}
+	return h
+}
+
+// TestRenderStateRowWidth checks that every row of the table lines up with its border
+// The suits take several bytes each, so the rows have to be padded by rune count.
+func TestRenderStateRowWidth(t *testing.T) {
+	surrendered := spotHand(0, Card{Ten, Spades}, Card{Six, Hearts})
+	surrendered.Surrendered = true
+
+	tests := []struct {
+		name  string
+		hands []*Hand
+	}{
+		{"one hand", []*Hand{spotHand(0, Card{Ten, Spades}, Card{Nine, Hearts})}},
+		{"bust", []*Hand{spotHand(0, Card{Ten, Spades}, Card{Six, Hearts}, Card{King, Clubs})}},
+		{"surrendered", []*Hand{surrendered}},
+		{"split", []*Hand{
+			spotHand(0, Card{Eight, Spades}, Card{Three, Diamonds}),
+			spotHand(0, Card{Eight, Clubs}, Card{Two, Hearts}),
+		}},
+		{"spots", []*Hand{
+			spotHand(0, Card{Ace, Spades}, Card{King, Diamonds}),
+			spotHand(1, Card{Eight, Clubs}, Card{Two, Hearts}),
+			spotHand(1, Card{Eight, Hearts}, Card{Ten, Clubs}),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGameWithSeed(DefaultRules(), 1)
+			g.DealerHand = spotHand(0, Card{Seven, Clubs}, Card{Queen, Hearts})
+			g.PlayerHands = tt.hands
+
+			for _, hide := range []bool{true, false} {
+				rows := strings.Split(RenderState(g, hide), "\n")
+				width := utf8.RuneCountInString(rows[0])
+				for _, row := range rows {
+					if n := utf8.RuneCountInString(row); n != width {
+						t.Errorf("row %q is %d wide, expected the border's %d", row, n, width)
+					}
+				}
+			}
+		})
+	}
+}
@@ -3,26 +3,86 @@ package game
 import (
 	cryptorand "crypto/rand"
 	"encoding/binary"
+	"fmt"
 	"math/rand"
 	"os"
+	"time"
 )
 
-// CryptoSeededRand creates a new rand.Rand seeded with crypto/rand
-func CryptoSeededRand() *rand.Rand {
+// FixedSeed is the seed used when BLACKJACK_SEEDED=1
+const FixedSeed = 12345
+
+// CryptoSeed draws a random seed from crypto/rand
+func CryptoSeed() (int64, error) {
 	var seed int64
-	binary.Read(cryptorand.Reader, binary.BigEndian, &seed)
-	return rand.New(rand.NewSource(seed))
+	if err := binary.Read(cryptorand.Reader, binary.BigEndian, &seed); err != nil {
+		return 0, fmt.Errorf("reading a random seed: %w", err)
+	}
+	return seed, nil
 }
 
 // FixedSeededRand creates a new rand.Rand with a fixed seed for testing
 func FixedSeededRand() *rand.Rand {
-	return rand.New(rand.NewSource(12345))
+	return NewSeededRand(FixedSeed)
+}
+
+// NewSeededRand creates a new rand.Rand with the given seed
+func NewSeededRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// countingSource is a seeded source that counts the values drawn from it
+// A generator's state is then just its seed and the number of draws, which can be
+// saved and restored; the values are the same as rand.NewSource(seed) produces.
+type countingSource struct {
+	src   rand.Source64
+	draws uint64
+}
+
+// newCountingSource creates a source seeded with seed that has already made draws draws
+func newCountingSource(seed int64, draws uint64) *countingSource {
+	s := &countingSource{src: rand.NewSource(seed).(rand.Source64)}
+	for s.draws < draws {
+		s.Int63()
+	}
+	return s
+}
+
+func (s *countingSource) Int63() int64 {
+	s.draws++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.draws++
+	return s.src.Uint64()
 }
 
-// NewRand creates a new rand.Rand based on the BLACKJACK_SEEDED environment variable
-func NewRand() *rand.Rand {
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.draws = 0
+}
+
+// DeriveSeed derives an independent seed for a numbered stream from a master seed
+// It applies the SplitMix64 finalizer, so neighbouring streams get unrelated seeds.
+func DeriveSeed(master int64, stream uint64) int64 {
+	z := uint64(master) + (stream+1)*0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return int64(z ^ (z >> 31))
+}
+
+// NewSeed returns a seed based on the BLACKJACK_SEEDED environment variable:
+// FixedSeed when it is set to 1, otherwise a seed drawn from crypto/rand
+// If crypto/rand can't be read the seed is taken from the clock instead and the error
+// says so. The seed can still be used; the caller decides how to warn about it.
+func NewSeed() (int64, error) {
 	if os.Getenv("BLACKJACK_SEEDED") == "1" {
-		return FixedSeededRand()
+		return FixedSeed, nil
+	}
+	seed, err := CryptoSeed()
+	if err != nil {
+		return time.Now().UnixNano(), fmt.Errorf("%w; using a seed from the clock", err)
 	}
-	return CryptoSeededRand()
+	return seed, nil
 }
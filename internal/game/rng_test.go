@@ -0,0 +1,30 @@
+package game
+
+import (
+	cryptorand "crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("no entropy")
+}
+
+// TestNewSeedReportsClockFallback checks that a seed taken from the clock is reported
+func TestNewSeedReportsClockFallback(t *testing.T) {
+	t.Setenv("BLACKJACK_SEEDED", "")
+	reader := cryptorand.Reader
+	cryptorand.Reader = failingReader{}
+	defer func() { cryptorand.Reader = reader }()
+
+	seed, err := NewSeed()
+	if err == nil || !strings.Contains(err.Error(), "no entropy") || !strings.Contains(err.Error(), "clock") {
+		t.Errorf("expected the fallback to be reported, got %v", err)
+	}
+	if seed == 0 {
+		t.Error("no seed was taken from the clock")
+	}
+}
@@ -1,14 +1,355 @@
 package game
 
-// Game rules constants
+import (
+	"fmt"
+	"strings"
+)
+
+// Game constants that do not vary between tables
+const (
+	InsurancePayout = 2.0 // 2:1 payout
+	StartingBank    = 1000
+)
+
+// DoubleRule restricts which first two-card hands may be doubled
+type DoubleRule int
+
 const (
-	DealerStandsSoft17 = true // S17 rule
-	BlackjackPayout    = 1.5  // 3:2 payout
-	InsurancePayout    = 2.0  // 2:1 payout
-	MinBet             = 1
-	StartingBank       = 1000
+	DoubleAny    DoubleRule = iota // Double on any first two cards
+	Double9To11                    // Double on totals of 9, 10 or 11 only
+	Double10To11                   // Double on totals of 10 or 11 only
 )
 
+func (d DoubleRule) String() string {
+	switch d {
+	case DoubleAny:
+		return "double any two"
+	case Double9To11:
+		return "double 9-11"
+	case Double10To11:
+		return "double 10-11"
+	default:
+		return "unknown double rule"
+	}
+}
+
+// MarshalText encodes the double rule as "any", "9-11" or "10-11"
+func (d DoubleRule) MarshalText() ([]byte, error) {
+	switch d {
+	case DoubleAny:
+		return []byte("any"), nil
+	case Double9To11:
+		return []byte("9-11"), nil
+	case Double10To11:
+		return []byte("10-11"), nil
+	default:
+		return nil, fmt.Errorf("invalid double rule: %d", int(d))
+	}
+}
+
+// UnmarshalText decodes a double rule written by MarshalText
+func (d *DoubleRule) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "any":
+		*d = DoubleAny
+	case "9-11":
+		*d = Double9To11
+	case "10-11":
+		*d = Double10To11
+	default:
+		return fmt.Errorf("invalid double rule: %s", text)
+	}
+	return nil
+}
+
+// SurrenderType represents which form of surrender the table offers
+type SurrenderType int
+
+const (
+	SurrenderNone  SurrenderType = iota
+	SurrenderLate                // Surrender after the dealer peeks for blackjack
+	SurrenderEarly               // Surrender before the dealer peeks for blackjack
+)
+
+func (s SurrenderType) String() string {
+	switch s {
+	case SurrenderNone:
+		return "no surrender"
+	case SurrenderLate:
+		return "late surrender"
+	case SurrenderEarly:
+		return "early surrender"
+	default:
+		return "unknown surrender"
+	}
+}
+
+// MarshalText encodes the surrender type as "none", "late" or "early"
+func (s SurrenderType) MarshalText() ([]byte, error) {
+	switch s {
+	case SurrenderNone:
+		return []byte("none"), nil
+	case SurrenderLate:
+		return []byte("late"), nil
+	case SurrenderEarly:
+		return []byte("early"), nil
+	default:
+		return nil, fmt.Errorf("invalid surrender type: %d", int(s))
+	}
+}
+
+// UnmarshalText decodes a surrender type written by MarshalText
+func (s *SurrenderType) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "none":
+		*s = SurrenderNone
+	case "late":
+		*s = SurrenderLate
+	case "early":
+		*s = SurrenderEarly
+	default:
+		return fmt.Errorf("invalid surrender type: %s", text)
+	}
+	return nil
+}
+
+// RuleSet describes the house rules of a table
+type RuleSet struct {
+	Name             string        `json:"name"`
+	DealerHitsSoft17 bool          `json:"dealer_hits_soft_17"` // H17 when true, S17 when false
+	NumDecks         int           `json:"num_decks"`           // Number of 52-card decks in play
+	Penetration      float64       `json:"penetration"`         // Fraction of the shoe dealt before the cut card comes out
+	BlackjackPayout  float64       `json:"blackjack_payout"`    // 1.5 for 3:2, 1.2 for 6:5
+	DoubleRule       DoubleRule    `json:"double_rule"`
+	DoubleAfterSplit bool          `json:"double_after_split"` // DAS
+	MaxSplitHands    int           `json:"max_split_hands"`    // Maximum number of hands after splitting (1 disables splitting)
+	ResplitAces      bool          `json:"resplit_aces"`       // RSA
+	Surrender        SurrenderType `json:"surrender"`
+	DealerPeeks      bool          `json:"dealer_peeks"` // False for European no-hole-card games
+	MinBet           int           `json:"min_bet"`
+	MaxBet           int           `json:"max_bet"` // 0 means no table maximum
+}
+
+// DefaultRules returns the classic single-deck rule set the game has always used
+func DefaultRules() RuleSet {
+	return RuleSet{
+		Name:             "Classic",
+		DealerHitsSoft17: false,
+		NumDecks:         1,
+		Penetration:      0.75,
+		BlackjackPayout:  1.5,
+		DoubleRule:       DoubleAny,
+		DoubleAfterSplit: true,
+		MaxSplitHands:    4,
+		ResplitAces:      false,
+		Surrender:        SurrenderLate,
+		DealerPeeks:      true,
+		MinBet:           1,
+		MaxBet:           0,
+	}
+}
+
+// VegasStripRules returns a typical Las Vegas Strip shoe game
+func VegasStripRules() RuleSet {
+	return RuleSet{
+		Name:             "Vegas Strip",
+		DealerHitsSoft17: false,
+		NumDecks:         4,
+		Penetration:      0.75,
+		BlackjackPayout:  1.5,
+		DoubleRule:       DoubleAny,
+		DoubleAfterSplit: true,
+		MaxSplitHands:    4,
+		ResplitAces:      false,
+		Surrender:        SurrenderLate,
+		DealerPeeks:      true,
+		MinBet:           10,
+		MaxBet:           5000,
+	}
+}
+
+// AtlanticCityRules returns the standard Atlantic City eight-deck game
+func AtlanticCityRules() RuleSet {
+	return RuleSet{
+		Name:             "Atlantic City",
+		DealerHitsSoft17: false,
+		NumDecks:         8,
+		Penetration:      0.70,
+		BlackjackPayout:  1.5,
+		DoubleRule:       DoubleAny,
+		DoubleAfterSplit: true,
+		MaxSplitHands:    4,
+		ResplitAces:      false,
+		Surrender:        SurrenderLate,
+		DealerPeeks:      true,
+		MinBet:           10,
+		MaxBet:           2000,
+	}
+}
+
+// DowntownRules returns a downtown Las Vegas double-deck H17 game
+func DowntownRules() RuleSet {
+	return RuleSet{
+		Name:             "Downtown",
+		DealerHitsSoft17: true,
+		NumDecks:         2,
+		Penetration:      0.65,
+		BlackjackPayout:  1.5,
+		DoubleRule:       DoubleAny,
+		DoubleAfterSplit: true,
+		MaxSplitHands:    4,
+		ResplitAces:      false,
+		Surrender:        SurrenderNone,
+		DealerPeeks:      true,
+		MinBet:           5,
+		MaxBet:           1000,
+	}
+}
+
+// EuropeanRules returns a European no-hole-card game
+func EuropeanRules() RuleSet {
+	return RuleSet{
+		Name:             "European",
+		DealerHitsSoft17: false,
+		NumDecks:         6,
+		Penetration:      0.70,
+		BlackjackPayout:  1.5,
+		DoubleRule:       Double9To11,
+		DoubleAfterSplit: true,
+		MaxSplitHands:    2,
+		ResplitAces:      false,
+		Surrender:        SurrenderNone,
+		DealerPeeks:      false,
+		MinBet:           5,
+		MaxBet:           1000,
+	}
+}
+
+// Presets returns all of the named rule sets
+func Presets() []RuleSet {
+	return []RuleSet{
+		DefaultRules(),
+		VegasStripRules(),
+		AtlanticCityRules(),
+		DowntownRules(),
+		EuropeanRules(),
+	}
+}
+
+// RulesByName looks up a preset by name, ignoring case, spaces, hyphens and underscores
+func RulesByName(name string) (RuleSet, error) {
+	key := normalizeRuleName(name)
+	for _, rules := range Presets() {
+		if normalizeRuleName(rules.Name) == key {
+			return rules, nil
+		}
+	}
+
+	names := make([]string, 0, len(Presets()))
+	for _, rules := range Presets() {
+		names = append(names, rules.Name)
+	}
+	return RuleSet{}, fmt.Errorf("unknown rule set %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+func normalizeRuleName(name string) string {
+	name = strings.ToLower(name)
+	return strings.NewReplacer(" ", "", "-", "", "_", "").Replace(name)
+}
+
+// Validate checks that the rule set describes a playable table
+func (r RuleSet) Validate() error {
+	if r.NumDecks < 1 || r.NumDecks > 8 {
+		return fmt.Errorf("number of decks must be between 1 and 8")
+	}
+	if r.Penetration <= 0 || r.Penetration > 1 {
+		return fmt.Errorf("penetration must be greater than 0 and at most 1")
+	}
+	if r.BlackjackPayout <= 0 {
+		return fmt.Errorf("blackjack payout must be positive")
+	}
+	if r.MaxSplitHands < 1 {
+		return fmt.Errorf("max split hands must be at least 1")
+	}
+	if r.MinBet < 1 {
+		return fmt.Errorf("minimum bet must be at least 1")
+	}
+	if r.MaxBet != 0 && r.MaxBet < r.MinBet {
+		return fmt.Errorf("maximum bet cannot be below the minimum bet")
+	}
+	return nil
+}
+
+// Soft17Rule describes how the dealer plays soft 17, e.g. "Dealer hits soft 17 (H17)"
+func (r RuleSet) Soft17Rule() string {
+	if r.DealerHitsSoft17 {
+		return "Dealer hits soft 17 (H17)"
+	}
+	return "Dealer stands on soft 17 (S17)"
+}
+
+// Summary returns a one-line description of the rules, e.g. "6 decks, S17, 3:2, DAS"
+func (r RuleSet) Summary() string {
+	parts := make([]string, 0, 8)
+
+	if r.NumDecks == 1 {
+		parts = append(parts, "1 deck")
+	} else {
+		parts = append(parts, fmt.Sprintf("%d decks", r.NumDecks))
+	}
+	parts = append(parts, fmt.Sprintf("%.0f%% penetration", r.Penetration*100))
+
+	if r.DealerHitsSoft17 {
+		parts = append(parts, "H17")
+	} else {
+		parts = append(parts, "S17")
+	}
+
+	parts = append(parts, "BJ pays "+payoutRatio(r.BlackjackPayout))
+	parts = append(parts, r.DoubleRule.String())
+
+	if r.DoubleAfterSplit {
+		parts = append(parts, "DAS")
+	} else {
+		parts = append(parts, "no DAS")
+	}
+
+	if r.MaxSplitHands > 1 {
+		parts = append(parts, fmt.Sprintf("split to %d", r.MaxSplitHands))
+	} else {
+		parts = append(parts, "no splitting")
+	}
+
+	if r.ResplitAces {
+		parts = append(parts, "RSA")
+	}
+
+	parts = append(parts, r.Surrender.String())
+
+	if !r.DealerPeeks {
+		parts = append(parts, "no hole card")
+	}
+
+	if r.MaxBet > 0 {
+		parts = append(parts, fmt.Sprintf("bets %d-%d", r.MinBet, r.MaxBet))
+	} else {
+		parts = append(parts, fmt.Sprintf("min bet %d", r.MinBet))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// payoutRatio formats a payout multiplier as a ratio, e.g. 1.5 -> "3:2"
+func payoutRatio(payout float64) string {
+	for den := 1; den <= 10; den++ {
+		num := payout * float64(den)
+		if num == float64(int(num)) {
+			return fmt.Sprintf("%d:%d", int(num), den)
+		}
+	}
+	return fmt.Sprintf("%.2f:1", payout)
+}
+
 // Outcome represents the result of a hand
 type Outcome int
 
@@ -37,20 +378,46 @@ func (o Outcome) String() string {
 	}
 }
 
-// Payout calculates the payout for a given outcome and bet
-// Returns the delta to the bank (positive for win, negative for loss)
-func Payout(outcome Outcome, bet int, isInsurance bool) int {
+// ParseOutcome parses an outcome name such as "Win" or "blackjack"
+func ParseOutcome(s string) (Outcome, error) {
+	for _, outcome := range []Outcome{OutcomeWin, OutcomeLose, OutcomePush, OutcomeBlackjack, OutcomeSurrender} {
+		if strings.EqualFold(s, outcome.String()) {
+			return outcome, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid outcome: %s", s)
+}
+
+// MarshalText encodes the outcome by name
+func (o Outcome) MarshalText() ([]byte, error) {
+	return []byte(o.String()), nil
+}
+
+// UnmarshalText decodes an outcome name
+func (o *Outcome) UnmarshalText(text []byte) error {
+	outcome, err := ParseOutcome(string(text))
+	if err != nil {
+		return err
+	}
+	*o = outcome
+	return nil
+}
+
+// Payout calculates the payout for a given outcome and bet under these rules
+// Returns the chips returned to the bank, including the stake (0 when the bet loses)
+func (r RuleSet) Payout(outcome Outcome, bet int, isInsurance bool) int {
 	if isInsurance {
 		if outcome == OutcomeWin {
-			return int(float64(bet) * InsurancePayout)
+			// Insurance pays 2:1 (bet + 2x bet)
+			return bet + int(float64(bet)*InsurancePayout)
 		}
-		return -bet
+		return 0
 	}
 
 	switch outcome {
 	case OutcomeBlackjack:
-		// Natural blackjack pays 3:2 (bet + 1.5x bet)
-		return int(float64(bet) * (1 + BlackjackPayout))
+		// Natural blackjack pays the table's ratio (bet + 1.5x bet at 3:2)
+		return int(float64(bet) * (1 + r.BlackjackPayout))
 	case OutcomeWin:
 		// Regular win pays 1:1 (bet + bet)
 		return bet + bet
@@ -80,6 +447,11 @@ func DetermineOutcome(playerHand, dealerHand *Hand) Outcome {
 		return OutcomeLose
 	}
 
+	// Natural blackjack (only on initial 2-card hand) pays 3:2 even if the dealer busts
+	if playerHand.IsBlackjack() && !dealerHand.IsBlackjack() {
+		return OutcomeBlackjack
+	}
+
 	// Dealer bust, player wins
 	if dealerHand.IsBust() {
 		return OutcomeWin
@@ -88,11 +460,6 @@ func DetermineOutcome(playerHand, dealerHand *Hand) Outcome {
 	playerValue := playerHand.Value()
 	dealerValue := dealerHand.Value()
 
-	// Natural blackjack (only on initial 2-card hand)
-	if playerHand.IsBlackjack() && !dealerHand.IsBlackjack() {
-		return OutcomeBlackjack
-	}
-
 	// Dealer blackjack beats non-blackjack
 	if dealerHand.IsBlackjack() && !playerHand.IsBlackjack() {
 		return OutcomeLose
@@ -0,0 +1,114 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+)
+
+// gameState is the JSON form of a Game
+// The random number generator is stored as its seed and the number of values drawn
+// from it, so a restored game deals exactly the cards the original would have.
+type gameState struct {
+	Rules              RuleSet      `json:"rules"`
+	Bank               int          `json:"bank"`
+	Seed               int64        `json:"seed"`
+	RNGDraws           uint64       `json:"rng_draws"`
+	Shoe               *Shoe        `json:"shoe"`
+	PlayerHands        []*Hand      `json:"player_hands"`
+	DealerHand         *Hand        `json:"dealer_hand"`
+	CurrentPhase       Phase        `json:"phase"`
+	ActiveHandIndex    int          `json:"active_hand_index"`
+	DealerHasBlackjack bool         `json:"dealer_has_blackjack"`
+	InsuranceOffered   bool         `json:"insurance_offered"`
+	PeekPending        bool         `json:"peek_pending"`
+	HoleCardRevealed   bool         `json:"hole_card_revealed"`
+	Counter            *Counter     `json:"counter,omitempty"`
+	RoundsPlayed       int          `json:"rounds_played"`
+	Ledger             *Ledger      `json:"ledger"`
+	Record             *RoundRecord `json:"record,omitempty"`
+	RecordDone         bool         `json:"record_done,omitempty"`
+}
+
+// MarshalJSON encodes the complete game state, including the shoe and the RNG position
+func (g *Game) MarshalJSON() ([]byte, error) {
+	if g.rngSource == nil {
+		return nil, fmt.Errorf("game RNG state cannot be saved")
+	}
+	return json.Marshal(gameState{
+		Rules:              g.Rules,
+		Bank:               g.Bank,
+		Seed:               g.Seed,
+		RNGDraws:           g.rngSource.draws,
+		Shoe:               g.Shoe,
+		PlayerHands:        g.PlayerHands,
+		DealerHand:         g.DealerHand,
+		CurrentPhase:       g.CurrentPhase,
+		ActiveHandIndex:    g.ActiveHandIndex,
+		DealerHasBlackjack: g.DealerHasBlackjack,
+		InsuranceOffered:   g.InsuranceOffered,
+		PeekPending:        g.PeekPending,
+		HoleCardRevealed:   g.HoleCardRevealed,
+		Counter:            g.Counter,
+		RoundsPlayed:       g.RoundsPlayed,
+		Ledger:             &g.Ledger,
+		Record:             g.record,
+		RecordDone:         g.recordDone,
+	})
+}
+
+// UnmarshalJSON restores a game written by MarshalJSON
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var state gameState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	if err := state.Rules.Validate(); err != nil {
+		return fmt.Errorf("saved game: %w", err)
+	}
+	if state.Shoe == nil {
+		return fmt.Errorf("saved game has no shoe")
+	}
+	if state.DealerHand == nil {
+		state.DealerHand = NewHand(0)
+	}
+	inRound := state.CurrentPhase != PhaseBetting && state.CurrentPhase != PhaseGameOver
+	if inRound && (len(state.PlayerHands) == 0 || len(state.DealerHand.Cards) < 2) {
+		return fmt.Errorf("saved game is in the %s phase but no cards are dealt", state.CurrentPhase)
+	}
+	if state.CurrentPhase == PhasePlayerAction && (state.ActiveHandIndex < 0 || state.ActiveHandIndex >= len(state.PlayerHands)) {
+		return fmt.Errorf("saved game has invalid active hand %d", state.ActiveHandIndex)
+	}
+
+	if state.Ledger == nil {
+		state.Ledger = &Ledger{Opening: state.Bank}
+	}
+
+	source := newCountingSource(state.Seed, state.RNGDraws)
+	rng := rand.New(source)
+	state.Shoe.rng = rng
+
+	*g = Game{
+		Rules:              state.Rules,
+		Bank:               state.Bank,
+		Shoe:               state.Shoe,
+		PlayerHands:        state.PlayerHands,
+		DealerHand:         state.DealerHand,
+		CurrentPhase:       state.CurrentPhase,
+		ActiveHandIndex:    state.ActiveHandIndex,
+		RNG:                rng,
+		Seed:               state.Seed,
+		DealerHasBlackjack: state.DealerHasBlackjack,
+		InsuranceOffered:   state.InsuranceOffered,
+		PeekPending:        state.PeekPending,
+		HoleCardRevealed:   state.HoleCardRevealed,
+		Counter:            state.Counter,
+		RoundsPlayed:       state.RoundsPlayed,
+		Ledger:             *state.Ledger,
+		rngSource:          source,
+		record:             state.Record,
+		recordDone:         state.RecordDone,
+	}
+	return nil
+}
@@ -0,0 +1,281 @@
+package game
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// scenario is one round scripted by a file in testdata
+// Each line of a scenario file is "key: value"; blank lines and lines starting with #
+// are ignored. The keys are:
+//
+//	rules:           preset name, as for -rules (default classic)
+//	soft17:          "hit" or "stand", overriding the preset
+//	surrender:       "none", "late" or "early", overriding the preset
+//	cards:           the shoe in dealing order, in ParseCards notation; may be repeated
+//	bets:            one bet for each spot
+//	insurance:       the insurance bet for the next spot asked; repeated for each spot
+//	action:          the next decision, optionally followed by the exact actions
+//	                 that must be offered for it in parentheses: "stand (hit stand double)"
+//	expect_bank:     the bank once the round is settled
+//	expect_outcomes: the outcome of each hand, in order
+//	expect_dealer:   the dealer's final hand, hole card first
+//
+// Every card, insurance answer and action must be used by the round.
+type scenario struct {
+	rules     RuleSet
+	cards     []Card
+	bets      []int
+	insurance []int
+	actions   []scriptedAction
+	bank      int
+	outcomes  []Outcome
+	dealer    []Card
+}
+
+// scriptedAction is one decision from a scenario and, if given, the actions that must be offered
+type scriptedAction struct {
+	action  Action
+	offered []Action
+}
+
+func TestScenarios(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatal("no scenarios found in testdata")
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		t.Run(name, func(t *testing.T) {
+			s, err := loadScenario(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.run(t)
+		})
+	}
+}
+
+// loadScenario reads a scenario file
+func loadScenario(path string) (*scenario, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := &scenario{rules: DefaultRules(), bank: -1}
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected \"key: value\"", path, n)
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		seen[key] = true
+		if err := s.set(key, value); err != nil {
+			return nil, fmt.Errorf("%s:%d: %v", path, n, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range []string{"cards", "bets", "expect_bank", "expect_outcomes", "expect_dealer"} {
+		if !seen[key] {
+			return nil, fmt.Errorf("%s: missing %s", path, key)
+		}
+	}
+	return s, nil
+}
+
+// set applies one line of a scenario file
+// The rules line must come before any line overriding a rule.
+func (s *scenario) set(key string, value string) error {
+	var err error
+	switch key {
+	case "rules":
+		s.rules, err = RulesByName(value)
+	case "soft17":
+		switch value {
+		case "hit":
+			s.rules.DealerHitsSoft17 = true
+		case "stand":
+			s.rules.DealerHitsSoft17 = false
+		default:
+			err = fmt.Errorf("soft17 must be \"hit\" or \"stand\", not %q", value)
+		}
+	case "surrender":
+		err = s.rules.Surrender.UnmarshalText([]byte(value))
+	case "cards":
+		var cards []Card
+		cards, err = ParseCards(value)
+		s.cards = append(s.cards, cards...)
+	case "bets":
+		s.bets, err = parseInts(value)
+	case "insurance":
+		var bet int
+		bet, err = strconv.Atoi(value)
+		s.insurance = append(s.insurance, bet)
+	case "action":
+		var a scriptedAction
+		a, err = parseScriptedAction(value)
+		s.actions = append(s.actions, a)
+	case "expect_bank":
+		s.bank, err = strconv.Atoi(value)
+	case "expect_outcomes":
+		for _, field := range strings.Fields(value) {
+			var outcome Outcome
+			outcome, err = ParseOutcome(field)
+			if err != nil {
+				break
+			}
+			s.outcomes = append(s.outcomes, outcome)
+		}
+	case "expect_dealer":
+		s.dealer, err = ParseCards(value)
+	default:
+		err = fmt.Errorf("unknown key %q", key)
+	}
+	return err
+}
+
+func parseInts(value string) ([]int, error) {
+	var ints []int
+	for _, field := range strings.Fields(value) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, err
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
+
+// parseScriptedAction parses a decision such as "split" or "stand (hit stand double)"
+func parseScriptedAction(value string) (scriptedAction, error) {
+	name, offered, hasOffered := strings.Cut(value, "(")
+	action, err := ParseAction(strings.TrimSpace(name))
+	if err != nil {
+		return scriptedAction{}, err
+	}
+	a := scriptedAction{action: action}
+	if hasOffered {
+		list, ok := strings.CutSuffix(strings.TrimSpace(offered), ")")
+		if !ok {
+			return scriptedAction{}, fmt.Errorf("missing ) after the offered actions")
+		}
+		for _, field := range strings.Fields(list) {
+			offer, err := ParseAction(field)
+			if err != nil {
+				return scriptedAction{}, err
+			}
+			a.offered = append(a.offered, offer)
+		}
+	}
+	return a, nil
+}
+
+// run plays the scenario's round and checks the result
+func (s *scenario) run(t *testing.T) {
+	g := NewGameWithSeed(s.rules, FixedSeed)
+	g.Shoe = NewStackedShoe(s.cards, g.RNG)
+
+	p := &scriptedPlayer{s: s}
+	if err := g.PlayRound(p); err != nil {
+		t.Fatal(err)
+	}
+	if g.CurrentPhase != PhaseBetting && g.CurrentPhase != PhaseGameOver {
+		t.Fatalf("the round stopped in the %s phase", g.CurrentPhase)
+	}
+
+	if p.insured < len(s.insurance) {
+		t.Errorf("%d insurance answers were never asked for", len(s.insurance)-p.insured)
+	}
+	if p.acted < len(s.actions) {
+		t.Errorf("%d actions were never asked for", len(s.actions)-p.acted)
+	}
+	if g.Shoe.Shuffles > 0 {
+		t.Errorf("the round needed more cards than the scenario gives")
+	}
+	if n := g.Shoe.Remaining(); n > 0 {
+		t.Errorf("%d cards were left undealt: %v", n, g.Shoe.Cards)
+	}
+
+	if g.Bank != s.bank {
+		t.Errorf("bank is %d, expected %d", g.Bank, s.bank)
+	}
+	if err := g.CheckBank(); err != nil {
+		t.Error(err)
+	}
+
+	rec := g.LastRound()
+	var outcomes []Outcome
+	for _, hand := range rec.Hands {
+		outcomes = append(outcomes, hand.Outcome)
+	}
+	if fmt.Sprint(outcomes) != fmt.Sprint(s.outcomes) {
+		t.Errorf("outcomes are %v, expected %v", outcomes, s.outcomes)
+	}
+	if fmt.Sprint(g.DealerHand.Cards) != fmt.Sprint(s.dealer) {
+		t.Errorf("dealer finished with %v, expected %v", g.DealerHand.Cards, s.dealer)
+	}
+}
+
+// scriptedPlayer makes the decisions a scenario lists, in order
+type scriptedPlayer struct {
+	s       *scenario
+	insured int // Insurance answers used
+	acted   int // Actions used
+}
+
+func (p *scriptedPlayer) Bet(g *Game) (int, error) {
+	return p.s.bets[0], nil
+}
+
+func (p *scriptedPlayer) Bets(g *Game) ([]int, error) {
+	return p.s.bets, nil
+}
+
+func (p *scriptedPlayer) Insurance(g *Game, max int) (int, error) {
+	if p.insured >= len(p.s.insurance) {
+		// Insurance is declined unless the scenario takes it
+		return 0, nil
+	}
+	bet := p.s.insurance[p.insured]
+	p.insured++
+	return bet, nil
+}
+
+func (p *scriptedPlayer) Act(g *Game, hand *Hand, available []Action) (Action, error) {
+	if p.acted >= len(p.s.actions) {
+		return 0, fmt.Errorf("hand %d %s needs an action but the scenario has none left", g.ActiveHandIndex+1, hand)
+	}
+	a := p.s.actions[p.acted]
+	p.acted++
+
+	if a.offered != nil && fmt.Sprint(a.offered) != fmt.Sprint(available) {
+		return 0, fmt.Errorf("action %d on hand %d %s: offered %v, expected %v",
+			p.acted, g.ActiveHandIndex+1, hand, available, a.offered)
+	}
+	if ContainsAction(available, a.action) {
+		return a.action, nil
+	}
+	return 0, fmt.Errorf("action %d on hand %d %s: %s is not offered (offered %v)",
+		p.acted, g.ActiveHandIndex+1, hand, a.action, available)
+}
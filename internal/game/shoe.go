@@ -0,0 +1,134 @@
+package game
+
+import (
+	"math/rand"
+)
+
+// Shoe holds the cards in play: the undealt cards, the cut card and the discard tray
+type Shoe struct {
+	Cards       []Card  `json:"cards"`            // Undealt cards, next card first
+	Discards    []Card  `json:"discards"`         // Discard tray
+	NumDecks    int     `json:"num_decks"`        // Number of 52-card decks in the shoe
+	Penetration float64 `json:"penetration"`      // Fraction of the shoe dealt before the cut card comes out
+	CutCard     int     `json:"cut_card"`         // Number of undealt cards left when the cut card comes out (0 for none)
+	CutCardOut  bool    `json:"cut_card_out"`     // True once the cut card has been reached; shuffle before the next round
+	Shuffles    int     `json:"shuffles"`         // Number of shuffles so far, including emergency reshuffles
+	Refill      int     `json:"refill,omitempty"` // Decks to refill a stacked shoe with once it runs out (0 to reshuffle the discards)
+	rng         *rand.Rand
+}
+
+// NewShoe creates and shuffles a shoe of numDecks decks with the cut card placed at the given penetration
+func NewShoe(numDecks int, penetration float64, rng *rand.Rand) *Shoe {
+	shoe := &Shoe{
+		Cards:       NewDecks(numDecks),
+		NumDecks:    numDecks,
+		Penetration: penetration,
+		rng:         rng,
+	}
+	shoe.Shuffle()
+	return shoe
+}
+
+// NewStackedShoe creates a shoe that deals the given cards in order, with no cut card
+// Once the stacked cards run out the discards are reshuffled like any other shoe
+func NewStackedShoe(cards []Card, rng *rand.Rand) *Shoe {
+	stacked := make([]Card, len(cards))
+	copy(stacked, cards)
+	return &Shoe{
+		Cards:       stacked,
+		NumDecks:    (len(cards) + 51) / 52,
+		Penetration: 1,
+		rng:         rng,
+	}
+}
+
+// RefillWith has a stacked shoe replaced by numDecks freshly shuffled decks, cut at the
+// given penetration, once its stacked cards run out
+func (s *Shoe) RefillWith(numDecks int, penetration float64) {
+	s.Refill = numDecks
+	s.Penetration = penetration
+}
+
+// Shuffle gathers the discards back into the shoe, shuffles and inserts the cut card
+// A stacked shoe due a refill is set aside for full decks instead, less the cards still
+// on the table, which will go to the discard tray of the new shoe.
+func (s *Shoe) Shuffle(onTable ...Card) {
+	if s.Refill > 0 {
+		s.Cards = NewDecks(s.Refill)
+		s.Discards = nil
+		s.NumDecks = s.Refill
+		s.Refill = 0
+		s.Remove(onTable...)
+	}
+	s.Cards = append(s.Cards, s.Discards...)
+	s.Discards = nil
+	Shuffle(s.Cards, s.rng)
+
+	s.CutCard = len(s.Cards) - int(float64(len(s.Cards))*s.Penetration)
+	s.CutCardOut = false
+	s.Shuffles++
+}
+
+// ReshuffleDiscards shuffles the discard tray into the end of the shoe
+// This is the emergency mid-round reshuffle used when the shoe runs out of cards;
+// cards currently on the table stay where they are.
+func (s *Shoe) ReshuffleDiscards() {
+	Shuffle(s.Discards, s.rng)
+	s.Cards = append(s.Cards, s.Discards...)
+	s.Discards = nil
+	s.CutCard = 0
+	s.CutCardOut = true
+	s.Shuffles++
+}
+
+// Draw deals the next card from the shoe
+// Returns false only if both the shoe and the discard tray are empty.
+func (s *Shoe) Draw() (Card, bool) {
+	if len(s.Cards) == 0 {
+		if len(s.Discards) == 0 {
+			return Card{}, false
+		}
+		s.ReshuffleDiscards()
+	}
+
+	card := s.Cards[0]
+	s.Cards = s.Cards[1:]
+
+	if len(s.Cards) <= s.CutCard {
+		s.CutCardOut = true
+	}
+
+	return card, true
+}
+
+// Discard places cards in the discard tray
+func (s *Shoe) Discard(cards ...Card) {
+	s.Discards = append(s.Discards, cards...)
+}
+
+// Remove takes one of each of the cards out of the undealt cards, where it is found
+func (s *Shoe) Remove(cards ...Card) {
+	for _, card := range cards {
+		for i, c := range s.Cards {
+			if c == card {
+				s.Cards = append(s.Cards[:i], s.Cards[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// NeedsShuffle returns true if the cut card has come out and the shoe should be shuffled
+func (s *Shoe) NeedsShuffle() bool {
+	return s.CutCardOut
+}
+
+// Remaining returns the number of undealt cards in the shoe
+func (s *Shoe) Remaining() int {
+	return len(s.Cards)
+}
+
+// DecksRemaining returns the number of undealt decks, in fractions of a deck
+func (s *Shoe) DecksRemaining() float64 {
+	return float64(len(s.Cards)) / 52
+}
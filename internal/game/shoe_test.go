@@ -0,0 +1,60 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+// TestRefillMidRound runs a stacked shoe out while the dealer is drawing
+// The fresh decks must leave out the cards already on the table and be cut at their real size.
+func TestRefillMidRound(t *testing.T) {
+	g := NewGameWithSeed(DefaultRules(), FixedSeed)
+	// 10 9 stands against 6 7, and the dealer's 13 has to draw from the refilled shoe
+	cards, err := ParseCards("10S 7C 9D 6H")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Shoe = NewStackedShoe(cards, g.RNG)
+	g.Shoe.RefillWith(g.Rules.NumDecks, g.Rules.Penetration)
+
+	p := &scriptedPlayer{s: &scenario{bets: []int{10}, actions: []scriptedAction{{action: ActionStand}}}}
+	if err := g.PlayRound(p); err != nil {
+		t.Fatal(err)
+	}
+
+	if !g.LastRound().Reshuffled {
+		t.Error("the refill wasn't recorded in the round")
+	}
+	if g.Shoe.Refill != 0 || g.Shoe.NumDecks != g.Rules.NumDecks {
+		t.Errorf("shoe has %d decks with a refill of %d left, expected %d and none", g.Shoe.NumDecks, g.Shoe.Refill, g.Rules.NumDecks)
+	}
+
+	// The table and the shoe together hold the full decks once over
+	all := append([]Card(nil), g.Shoe.Cards...)
+	for _, hand := range g.PlayerHands {
+		all = append(all, hand.Cards...)
+	}
+	all = append(all, g.DealerHand.Cards...)
+	if got, want := sortedCards(all), sortedCards(NewDecks(g.Rules.NumDecks)); got != want {
+		t.Errorf("shoe and table hold %s, expected %s", got, want)
+	}
+
+	// The cut card was placed in the 48 cards left after the four on the table came out
+	size := 52*g.Rules.NumDecks - 4
+	if want := size - int(float64(size)*g.Rules.Penetration); g.Shoe.CutCard != want {
+		t.Errorf("cut card is at %d, expected %d", g.Shoe.CutCard, want)
+	}
+	if err := g.CheckBank(); err != nil {
+		t.Error(err)
+	}
+}
+
+func sortedCards(cards []Card) string {
+	codes := make([]string, len(cards))
+	for i, card := range cards {
+		codes[i] = card.Code()
+	}
+	sort.Strings(codes)
+	return fmt.Sprint(codes)
+}
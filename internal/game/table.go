@@ -0,0 +1,317 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+)
+
+// MaxSeats is the number of seats at a table
+const MaxSeats = 7
+
+// Seat is one place at a table
+// Its Game holds the seat's own bank, bets, hands and ledger; the shoe and the dealer's
+// hand are shared with the rest of the table.
+type Seat struct {
+	Name   string
+	Player Player
+	Game   *Game
+	Left   bool // The player has left the table
+}
+
+// TableEventHandler receives a table's events along with the index of the seat they concern
+// Events that concern the whole table, such as dealer cards and shuffles, have seat -1.
+type TableEventHandler func(seat int, e Event)
+
+// Table seats several players against one dealer, dealing every seat from one shoe
+// Cards go round-robin in seat order and the dealer plays once every seat has finished,
+// so each player's decisions change the cards the players after them receive.
+type Table struct {
+	Rules            RuleSet
+	Shoe             *Shoe
+	DealerHand       *Hand
+	Seats            []*Seat
+	RNG              *rand.Rand
+	Seed             int64 // Seed of RNG, disclosed so sessions can be replayed
+	HoleCardRevealed bool
+
+	subscribers []TableEventHandler
+}
+
+// NewTable creates an empty table whose shoe is shuffled from the given seed
+func NewTable(rules RuleSet, seed int64) *Table {
+	rng := NewSeededRand(seed)
+	return &Table{
+		Rules:      rules,
+		Shoe:       NewShoe(rules.NumDecks, rules.Penetration, rng),
+		DealerHand: NewHand(0),
+		RNG:        rng,
+		Seed:       seed,
+	}
+}
+
+// Sit seats a new player with the starting bank in the first free seat
+// A seat is free once its last player has left. The seat's Player must be set before the
+// next round; it is left for the caller because a player such as a counting bot needs the
+// seat's game to be created. Players may only sit down between rounds.
+func (t *Table) Sit(name string) (*Seat, error) {
+	index := len(t.Seats)
+	for i, seat := range t.Seats {
+		if seat.Left {
+			index = i
+			break
+		}
+	}
+	if index >= MaxSeats {
+		return nil, fmt.Errorf("the table is full (%d seats)", MaxSeats)
+	}
+
+	g := &Game{
+		Rules:        t.Rules,
+		Bank:         StartingBank,
+		Shoe:         t.Shoe,
+		DealerHand:   t.DealerHand,
+		RNG:          t.RNG,
+		Seed:         t.Seed,
+		CurrentPhase: PhaseBetting,
+		Ledger:       Ledger{Opening: StartingBank},
+		table:        t,
+	}
+	seat := &Seat{Name: name, Game: g}
+	if index < len(t.Seats) {
+		t.Seats[index] = seat
+	} else {
+		t.Seats = append(t.Seats, seat)
+	}
+	return seat, nil
+}
+
+// Subscribe registers a handler for every event at the table from now on
+func (t *Table) Subscribe(handler TableEventHandler) {
+	t.subscribers = append(t.subscribers, handler)
+}
+
+// Playing returns the seats still at the table
+func (t *Table) Playing() []*Seat {
+	var seats []*Seat
+	for _, seat := range t.Seats {
+		if !seat.Left {
+			seats = append(seats, seat)
+		}
+	}
+	return seats
+}
+
+// PlayRound plays one round at the table, asking each seat for its decisions in seat order
+// Every seat bets first, then the cards are dealt, insurance is offered, each seat plays
+// its hands in turn and finally the dealer plays and every seat is paid. A seat whose
+// player quits at the bet, or whose bank can't cover the minimum bet, leaves the table;
+// ErrQuit is returned once every seat has left. Any other error from a player stops the
+// round where it is.
+func (t *Table) PlayRound() error {
+	for i, seat := range t.Seats {
+		if seat.Player == nil && !seat.Left {
+			return fmt.Errorf("seat %d has no player", i+1)
+		}
+	}
+
+	t.discardTable()
+	if t.Shoe.NeedsShuffle() {
+		t.Shoe.Shuffle()
+		t.publish(nil, ShoeShuffled{})
+	}
+
+	// Take every bet before any card is dealt
+	var playing []*Seat
+	var bets []int
+	for _, seat := range t.Playing() {
+		if seat.Game.CurrentPhase == PhaseGameOver {
+			seat.Left = true
+			continue
+		}
+		bet, err := seat.Player.Bet(seat.Game)
+		if errors.Is(err, ErrQuit) {
+			seat.Left = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := seat.Game.checkBets([]int{bet}); err != nil {
+			return fmt.Errorf("%s: %w", seat.Name, err)
+		}
+		playing = append(playing, seat)
+		bets = append(bets, bet)
+	}
+	if len(playing) == 0 {
+		return ErrQuit
+	}
+
+	for i, seat := range playing {
+		if err := seat.Game.placeBets([]int{bets[i]}); err != nil {
+			return fmt.Errorf("%s: %w", seat.Name, err)
+		}
+		seat.Game.DealerHand = t.DealerHand
+		seat.Game.record.Seat = t.seatIndex(seat.Game) + 1
+	}
+
+	// Deal round-robin: each seat, the dealer's hole card, each seat, the dealer's upcard
+	// The dealer's cards are drawn through the first seat's game, which shares the shoe.
+	dealer := playing[0].Game
+	for _, seat := range playing {
+		seat.Game.dealCard(seat.Game.PlayerHands[0])
+	}
+	dealer.dealHoleCard(t.DealerHand)
+	for _, seat := range playing {
+		seat.Game.dealCard(seat.Game.PlayerHands[0])
+	}
+	dealer.dealCard(t.DealerHand)
+	for _, seat := range playing {
+		seat.Game.recordInitialDeal()
+		seat.Game.openRound()
+	}
+
+	for _, seat := range playing {
+		if seat.Game.CurrentPhase == PhaseInsurance {
+			if err := seat.Game.offerInsurance(seat.Player); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Every seat answers the early surrender offer before anyone plays
+	chosen := make([]map[*Hand]Action, len(playing))
+	for i, seat := range playing {
+		if seat.Game.EarlySurrenderOffered() {
+			var err error
+			if chosen[i], err = seat.Game.offerSurrender(seat.Player); err != nil {
+				return err
+			}
+		}
+	}
+
+	for i, seat := range playing {
+		if err := seat.Game.playHands(seat.Player, chosen[i]); err != nil {
+			return err
+		}
+	}
+
+	t.playDealer(playing)
+	for _, seat := range playing {
+		seat.Game.CurrentPhase = PhaseResolution
+		seat.Game.ResolvePayouts()
+	}
+	return nil
+}
+
+// playDealer reveals the hole card and draws to the dealer's hand once every seat is finished
+// The dealer doesn't draw if every seat's hands are already settled.
+func (t *Table) playDealer(playing []*Seat) {
+	t.revealHoleCard()
+
+	if len(t.DealerHand.Cards) == 2 && t.DealerHand.IsBlackjack() {
+		return
+	}
+
+	live := false
+	for _, seat := range playing {
+		for _, hand := range seat.Game.PlayerHands {
+			if !hand.IsBust() && !hand.Surrendered && !hand.IsBlackjack() {
+				live = true
+			}
+		}
+	}
+	if !live {
+		return
+	}
+
+	dealer := playing[0].Game
+	for DealerShouldHit(t.DealerHand, t.Rules) {
+		if !dealer.dealCard(t.DealerHand) {
+			break
+		}
+	}
+}
+
+// revealHoleCard turns the dealer's hole card face up for every seat at once
+func (t *Table) revealHoleCard() {
+	if t.HoleCardRevealed || len(t.DealerHand.Cards) == 0 {
+		return
+	}
+	t.HoleCardRevealed = true
+	for _, seat := range t.Seats {
+		if seat.Game.DealerHand == t.DealerHand {
+			seat.Game.HoleCardRevealed = true
+		}
+	}
+	t.publish(nil, HoleCardRevealed{Card: t.DealerHand.Cards[0]})
+}
+
+// discardTable moves every seat's cards and the dealer's into the discard tray
+func (t *Table) discardTable() {
+	for _, seat := range t.Seats {
+		for _, hand := range seat.Game.PlayerHands {
+			t.Shoe.Discard(hand.Cards...)
+		}
+		seat.Game.PlayerHands = nil
+	}
+	t.Shoe.Discard(t.DealerHand.Cards...)
+	t.DealerHand = NewHand(0)
+	t.HoleCardRevealed = false
+}
+
+// publish delivers an event from a seat's game, or from the table itself when from is nil
+// Every seat still playing can see every card, so cards, shuffles and the hole card reach
+// each of their counters, subscribers and hand histories; everything else stays with the
+// seat it concerns.
+func (t *Table) publish(from *Game, e Event) {
+	seat := t.seatIndex(from)
+	switch e := e.(type) {
+	case CardDealt:
+		if e.Dealer {
+			seat = -1
+		}
+		for _, s := range t.Playing() {
+			if s.Game != from {
+				s.Game.recordCard(e.Card)
+			}
+			s.Game.deliver(e)
+		}
+	case ShoeShuffled:
+		seat = -1
+		for _, s := range t.Playing() {
+			if e.Emergency && s.Game != from {
+				s.Game.recordReshuffle()
+			}
+			s.Game.deliver(e)
+		}
+	case HoleCardRevealed:
+		for _, s := range t.Playing() {
+			s.Game.deliver(e)
+		}
+	default:
+		from.deliver(e)
+	}
+
+	for _, handler := range t.subscribers {
+		handler(seat, e)
+	}
+}
+
+// seatIndex returns the index of the seat playing the game, or -1
+func (t *Table) seatIndex(g *Game) int {
+	for i, seat := range t.Seats {
+		if seat.Game == g {
+			return i
+		}
+	}
+	return -1
+}
+
+// SeatIndex returns the index of the game's seat at its table, or 0 when it isn't at one
+func (g *Game) SeatIndex() int {
+	if g.table == nil {
+		return 0
+	}
+	return g.table.seatIndex(g)
+}
@@ -0,0 +1,60 @@
+package game
+
+import "testing"
+
+// quitter leaves the table at its first bet
+type quitter struct{}
+
+func (quitter) Bet(g *Game) (int, error) {
+	return 0, ErrQuit
+}
+
+func (quitter) Insurance(g *Game, max int) (int, error) {
+	return 0, nil
+}
+
+func (quitter) Act(g *Game, hand *Hand, available []Action) (Action, error) {
+	return ActionStand, nil
+}
+
+// TestLeftSeatsSeeNothing checks that a seat that has left gets none of the table's events
+func TestLeftSeatsSeeNothing(t *testing.T) {
+	table := NewTable(DefaultRules(), FixedSeed)
+	// 10 9 stands against the dealer's 10 7
+	cards, err := ParseCards("10S 10C 9D 7H")
+	if err != nil {
+		t.Fatal(err)
+	}
+	table.Shoe = NewStackedShoe(cards, table.RNG)
+
+	player, err := table.Sit("player")
+	if err != nil {
+		t.Fatal(err)
+	}
+	player.Player = &scriptedPlayer{s: &scenario{bets: []int{10}, actions: []scriptedAction{{action: ActionStand}}}}
+	left, err := table.Sit("left")
+	if err != nil {
+		t.Fatal(err)
+	}
+	left.Player = quitter{}
+	left.Game.EnableCounting(CountHiLo)
+
+	var seen []Event
+	left.Game.Subscribe(func(e Event) { seen = append(seen, e) })
+	if err := table.PlayRound(); err != nil {
+		t.Fatal(err)
+	}
+
+	if !left.Left {
+		t.Fatal("the seat didn't leave")
+	}
+	if len(seen) > 0 {
+		t.Errorf("the seat that left was sent %v", seen)
+	}
+	if n := left.Game.Counter.Seen; n > 0 {
+		t.Errorf("the seat that left counted %d cards", n)
+	}
+	if player.Game.Bank != StartingBank+10 {
+		t.Errorf("the player's bank is %d, expected %d", player.Game.Bank, StartingBank+10)
+	}
+}
@@ -0,0 +1,62 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// Entry is one line of a hand-history file: a round and the session it belongs to
+type Entry struct {
+	Session string    `json:"session"`
+	Time    time.Time `json:"time"`
+	*game.RoundRecord
+}
+
+// Writer appends rounds to a JSON Lines hand-history file
+type Writer struct {
+	file    *os.File
+	enc     *json.Encoder
+	session string
+}
+
+// NewSessionID returns an identifier for a new session, e.g. "20261016T214500Z-12345"
+func NewSessionID(seed int64) string {
+	return fmt.Sprintf("%s-%d", time.Now().UTC().Format("20060102T150405Z"), seed)
+}
+
+// Open opens the hand-history file at path for appending, creating it if needed
+func Open(path string, session string) (*Writer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create history directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open history file: %w", err)
+	}
+
+	return &Writer{file: file, enc: json.NewEncoder(file), session: session}, nil
+}
+
+// Write appends one round as a single JSON line
+func (w *Writer) Write(rec *game.RoundRecord) error {
+	entry := Entry{
+		Session:     w.session,
+		Time:        time.Now().UTC(),
+		RoundRecord: rec,
+	}
+	if err := w.enc.Encode(entry); err != nil {
+		return fmt.Errorf("write history: %w", err)
+	}
+	return nil
+}
+
+// Close closes the hand-history file
+func (w *Writer) Close() error {
+	return w.file.Close()
+}
@@ -0,0 +1,286 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// Frame is one step of a replayed round
+type Frame struct {
+	Round  int
+	Step   string // What happened, e.g. "Hand 1 hits: 7♥ (17)"
+	Table  string // The table after the step, rendered with RenderState
+	Result string // The round's results, set on the final frame only
+}
+
+// Read loads every entry from a JSON Lines hand-history file
+func Read(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open history file: %w", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if entry.RoundRecord == nil {
+			return nil, fmt.Errorf("%s:%d: entry has no round", path, line)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read history file: %w", err)
+	}
+	return entries, nil
+}
+
+// Sessions returns the session IDs in the entries, in the order they first appear
+func Sessions(entries []Entry) []string {
+	var sessions []string
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		if !seen[entry.Session] {
+			seen[entry.Session] = true
+			sessions = append(sessions, entry.Session)
+		}
+	}
+	return sessions
+}
+
+// Session returns the entries belonging to one session
+func Session(entries []Entry, session string) []Entry {
+	var rounds []Entry
+	for _, entry := range entries {
+		if entry.Session == session {
+			rounds = append(rounds, entry)
+		}
+	}
+	return rounds
+}
+
+// Replay plays a recorded round back through the engine and returns it step by step
+// The round's cards are stacked into the shoe in the order they were dealt and the
+// recorded decisions are made again, so every frame is the real engine state. The
+// dealer's hole card stays hidden until the dealer's turn.
+//
+// A round from a multi-seat table is replayed from its seat's point of view: the record
+// has the other seats' cards but not their decisions, so only the cards that reached
+// this seat and the dealer are stacked.
+func Replay(rec *game.RoundRecord) ([]Frame, error) {
+	cards := rec.Cards
+	if rec.Seat != 0 {
+		cards = seatCards(rec)
+	}
+
+	g := game.NewGameWithSeed(rec.Rules, rec.Seed)
+	g.Shoe = game.NewStackedShoe(cards, game.NewSeededRand(rec.Seed))
+	g.Bank = rec.BankBefore
+	g.RoundsPlayed = rec.Round - 1
+
+	bets := rec.Spots
+	if len(bets) == 0 {
+		bets = []int{rec.Bet}
+	}
+	if err := g.StartHand(bets...); err != nil {
+		return nil, fmt.Errorf("round %d: %w", rec.Round, err)
+	}
+
+	r := &replayer{g: g, round: rec.Round}
+	seat := ""
+	if rec.Seat != 0 {
+		seat = fmt.Sprintf(", seat %d", rec.Seat)
+	}
+	spots := ""
+	if len(bets) > 1 {
+		spots = fmt.Sprintf(" on %d spots", len(bets))
+	}
+	r.frame(fmt.Sprintf("Round %d%s: bet %d chips%s from a bank of %d. Dealer shows %s.",
+		rec.Round, seat, rec.Bet, spots, rec.BankBefore, g.DealerUpcard()))
+
+	if g.CurrentPhase == game.PhaseInsurance {
+		if rec.Insurance != nil && rec.Insurance.Taken {
+			insurance := rec.Insurance.Spots
+			if len(insurance) == 0 {
+				insurance = []int{rec.Insurance.Bet}
+			}
+			if err := g.TakeInsurance(insurance...); err != nil {
+				return nil, fmt.Errorf("round %d: %w", rec.Round, err)
+			}
+			r.frame(fmt.Sprintf("Insurance taken for %d chips", rec.Insurance.Bet))
+		} else {
+			if err := g.DeclineInsurance(); err != nil {
+				return nil, fmt.Errorf("round %d: %w", rec.Round, err)
+			}
+			r.frame("Insurance declined")
+		}
+	}
+
+	if g.CurrentPhase == game.PhaseResolution {
+		g.ResolvePayouts()
+	}
+
+	// Spots surrendered early are recorded first, before any hand is played
+	actions := rec.Actions
+	if g.EarlySurrenderOffered() {
+		surrender := make([]bool, len(g.PlayerHands))
+		var surrendered []game.ActionRecord
+		for len(actions) > 0 && actions[0].Action == game.ActionSurrender &&
+			actions[0].Hand < len(surrender) && !surrender[actions[0].Hand] {
+			surrender[actions[0].Hand] = true
+			surrendered = append(surrendered, actions[0])
+			actions = actions[1:]
+		}
+		if err := g.EarlySurrender(surrender...); err != nil {
+			return nil, fmt.Errorf("round %d: %w", rec.Round, err)
+		}
+		for _, action := range surrendered {
+			r.frame(describeAction(action, g.PlayerHands[action.Hand]))
+		}
+	}
+
+	for _, action := range actions {
+		if g.CurrentPhase != game.PhasePlayerAction {
+			return nil, fmt.Errorf("round %d: %s recorded after the player's turn ended", rec.Round, action.Action)
+		}
+		if action.Hand != g.ActiveHandIndex {
+			return nil, fmt.Errorf("round %d: %s recorded on hand %d but hand %d is active",
+				rec.Round, action.Action, action.Hand+1, g.ActiveHandIndex+1)
+		}
+
+		hand := g.PlayerHands[action.Hand]
+		wasBust := hand.IsBust()
+		if err := g.PlayerAction(action.Action); err != nil {
+			return nil, fmt.Errorf("round %d: %s: %w", rec.Round, action.Action, err)
+		}
+		if wasBust {
+			// The CLI stands a busted hand to move on; there is nothing new to show
+			continue
+		}
+		r.frame(describeAction(action, g.PlayerHands[action.Hand]))
+	}
+
+	if g.CurrentPhase != game.PhaseBetting && g.CurrentPhase != game.PhaseGameOver {
+		return nil, fmt.Errorf("round %d: the recorded actions do not finish the round", rec.Round)
+	}
+	if g.Bank != rec.BankAfter {
+		return nil, fmt.Errorf("round %d: replay ends with a bank of %d, the history says %d", rec.Round, g.Bank, rec.BankAfter)
+	}
+
+	// At a table the dealer draws for the other seats even once this seat's hands are settled
+	if drawn := len(g.DealerHand.Cards) - 2; drawn < len(rec.DealerDraws) {
+		g.DealerHand.Cards = append(g.DealerHand.Cards, rec.DealerDraws[drawn:]...)
+	}
+
+	// The round is over; show the dealer's hand one card at a time
+	r.dealerTurn = true
+	r.dealerCards = 2
+	hole := g.DealerHand.Cards[0]
+	r.frame(fmt.Sprintf("Dealer turns over %s (%s)", hole, dealerTotal(g.DealerHand.Cards[:2])))
+	for n := 3; n <= len(g.DealerHand.Cards); n++ {
+		r.dealerCards = n
+		r.frame(fmt.Sprintf("Dealer draws %s (%s)", g.DealerHand.Cards[n-1], dealerTotal(g.DealerHand.Cards[:n])))
+	}
+
+	r.frames[len(r.frames)-1].Result = game.RenderResult(g)
+	return r.frames, nil
+}
+
+// seatCards returns the cards of a seat's round at a table in the order they would be
+// dealt with the seat alone against the dealer
+func seatCards(rec *game.RoundRecord) []game.Card {
+	spots := rec.InitialDeal.Spots
+	if len(spots) == 0 {
+		spots = [][]game.Card{rec.InitialDeal.Player}
+	}
+
+	var cards []game.Card
+	for _, spot := range spots {
+		cards = append(cards, spot[0])
+	}
+	cards = append(cards, rec.InitialDeal.DealerHole)
+	for _, spot := range spots {
+		cards = append(cards, spot[1])
+	}
+	cards = append(cards, rec.InitialDeal.DealerUpcard)
+	for _, action := range rec.Actions {
+		cards = append(cards, action.Cards...)
+	}
+	return append(cards, rec.DealerDraws...)
+}
+
+// replayer collects frames while a round is replayed
+// The engine plays the dealer as soon as the last hand finishes, so the dealer's
+// hand is cut back to what the table showed at each step before it is rendered.
+type replayer struct {
+	g           *game.Game
+	round       int
+	frames      []Frame
+	dealerTurn  bool // Set once the dealer's turn is being shown
+	dealerCards int  // Number of dealer cards on the table once the hole card is shown
+}
+
+func (r *replayer) frame(step string) {
+	dealer := r.g.DealerHand.Cards
+	hide := !r.dealerTurn
+	if hide {
+		r.g.DealerHand.Cards = dealer[:2]
+	} else {
+		r.g.DealerHand.Cards = dealer[:r.dealerCards]
+	}
+	table := game.RenderState(r.g, hide)
+	r.g.DealerHand.Cards = dealer
+
+	r.frames = append(r.frames, Frame{Round: r.round, Step: step, Table: table})
+}
+
+// describeAction describes an action and the cards it drew
+func describeAction(action game.ActionRecord, hand *game.Hand) string {
+	label := fmt.Sprintf("Hand %d", action.Hand+1)
+	switch action.Action {
+	case game.ActionHit:
+		return fmt.Sprintf("%s hits: %s (%s)", label, cardList(action.Cards), handTotal(hand))
+	case game.ActionDouble:
+		return fmt.Sprintf("%s doubles to %d chips: %s (%s)", label, hand.Bet, cardList(action.Cards), handTotal(hand))
+	case game.ActionSplit:
+		return fmt.Sprintf("%s splits into hands %d and %d: %s", label, action.Hand+1, action.NewHand+1, cardList(action.Cards))
+	case game.ActionSurrender:
+		return fmt.Sprintf("%s surrenders half the bet", label)
+	default:
+		return fmt.Sprintf("%s stands on %s", label, handTotal(hand))
+	}
+}
+
+func cardList(cards []game.Card) string {
+	s := ""
+	for i, card := range cards {
+		if i > 0 {
+			s += " and "
+		}
+		s += card.String()
+	}
+	return s
+}
+
+func handTotal(hand *game.Hand) string {
+	if hand.IsBust() {
+		return fmt.Sprintf("%d, BUST", hand.Value())
+	}
+	return fmt.Sprintf("%d", hand.Value())
+}
+
+func dealerTotal(cards []game.Card) string {
+	return handTotal(&game.Hand{Cards: cards})
+}
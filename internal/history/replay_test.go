@@ -0,0 +1,85 @@
+package history
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/strategy"
+)
+
+// TestReplayTableRounds replays every seat's rounds from a multi-seat table
+// Replay fails unless each seat's cards and decisions bring it to the bank it recorded.
+func TestReplayTableRounds(t *testing.T) {
+	table := game.NewTable(game.DefaultRules(), 11)
+	for _, name := range []string{"basic", "random", "count"} {
+		seat, err := table.Sit(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		seat.Player, err = strategy.NewBot(name, seat.Game, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	replayed := 0
+	for round := 1; round <= 200; round++ {
+		if err := table.PlayRound(); err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+		for _, seat := range table.Playing() {
+			rec := seat.Game.LastRound()
+			if rec == nil || rec.Round != round {
+				continue
+			}
+			frames, err := Replay(rec)
+			if err != nil {
+				t.Fatalf("seat %d: %v", rec.Seat, err)
+			}
+			if !strings.Contains(frames[0].Step, "seat") {
+				t.Errorf("seat %d round %d: first step %q doesn't name the seat", rec.Seat, round, frames[0].Step)
+			}
+			replayed++
+		}
+	}
+	if replayed == 0 {
+		t.Fatal("no rounds were replayed")
+	}
+}
+
+// twoSpots plays two spots a round at random
+type twoSpots struct {
+	*strategy.RandomBot
+}
+
+func (p twoSpots) Bets(g *game.Game) ([]int, error) {
+	return []int{10, 10}, nil
+}
+
+// TestReplayEarlySurrender replays rounds where several spots answer early surrender before the peek
+func TestReplayEarlySurrender(t *testing.T) {
+	rules := game.DefaultRules()
+	rules.Surrender = game.SurrenderEarly
+	g := game.NewGameWithBank(rules, 3, 100000)
+	p := twoSpots{strategy.NewRandomBot(10, game.NewSeededRand(3))}
+
+	surrendered := 0
+	for round := 1; round <= 300; round++ {
+		if err := g.PlayRound(p); err != nil {
+			t.Fatalf("round %d: %v", round, err)
+		}
+		rec := g.LastRound()
+		if _, err := Replay(rec); err != nil {
+			t.Fatal(err)
+		}
+		upcard := g.DealerUpcard()
+		peeked := upcard.IsAce() || upcard.Rank.Value() == 10
+		if peeked && len(rec.Actions) > 0 && rec.Actions[0].Hand == 1 && rec.Actions[0].Action == game.ActionSurrender {
+			surrendered++
+		}
+	}
+	if surrendered == 0 {
+		t.Fatal("the second spot never surrendered before the first spot played")
+	}
+}
@@ -0,0 +1,87 @@
+package save
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/strategy"
+)
+
+// Version is the save file format written by this build
+// Bump it whenever the format changes in a way older builds cannot read.
+const Version = 1
+
+// File is the contents of a save file
+type File struct {
+	Version      int               `json:"version"`
+	SavedAt      time.Time         `json:"saved_at"`
+	Session      string            `json:"session"` // Hand-history session ID, kept so history continues the session
+	Game         *game.Game        `json:"game"`
+	Trainer      *strategy.Trainer `json:"trainer,omitempty"`
+	CountVisible bool              `json:"count_visible,omitempty"`
+}
+
+// Write saves the session to path, replacing any earlier save
+// The file is written beside the old one and renamed over it, so a crash
+// never leaves a half-written save behind.
+func Write(path string, f *File) error {
+	f.Version = Version
+	f.SavedAt = time.Now().UTC()
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode save: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create save directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write save: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write save: %w", err)
+	}
+	return nil
+}
+
+// Read loads a save file
+// It returns an error satisfying errors.Is(err, fs.ErrNotExist) if there is none.
+func Read(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var header struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return nil, fmt.Errorf("read save: %w", err)
+	}
+	if header.Version != Version {
+		return nil, fmt.Errorf("save file version %d is not supported (expected %d)", header.Version, Version)
+	}
+
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("read save: %w", err)
+	}
+	if f.Game == nil {
+		return nil, fmt.Errorf("read save: no game")
+	}
+	return &f, nil
+}
+
+// Remove deletes the save file, if there is one
+func Remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package save
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// TestRoundTrip saves a game partway through a round and checks that it reads back whole
+func TestRoundTrip(t *testing.T) {
+	g := game.NewGameWithSeed(game.DefaultRules(), game.FixedSeed)
+	// Spot 1 is dealt 8 8 and spot 2 9 7 against a 6, with cards to spare for the split
+	cards, err := game.ParseCards("8H 9C 5C 8D 7S 6H 2C 3D 4S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Shoe = game.NewStackedShoe(cards, g.RNG)
+	g.Shoe.RefillWith(g.Rules.NumDecks, g.Rules.Penetration)
+	if err := g.StartHand(10, 20); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.PlayerAction(game.ActionSplit); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "save.json")
+	if err := Write(path, &File{Session: "test", Game: g}); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Read(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Version != Version {
+		t.Errorf("version is %d, expected %d", f.Version, Version)
+	}
+	restored := f.Game
+
+	if fmt.Sprint(restored.Ledger) != fmt.Sprint(g.Ledger) {
+		t.Errorf("ledger is %v, expected %v", restored.Ledger, g.Ledger)
+	}
+	if err := restored.CheckBank(); err != nil {
+		t.Error(err)
+	}
+	if len(restored.PlayerHands) != len(g.PlayerHands) {
+		t.Fatalf("%d hands restored, expected %d", len(restored.PlayerHands), len(g.PlayerHands))
+	}
+	for i, hand := range g.PlayerHands {
+		if got := restored.PlayerHands[i]; got.Spot != hand.Spot || got.String() != hand.String() {
+			t.Errorf("hand %d is %s on spot %d, expected %s on spot %d", i+1, got, got.Spot, hand, hand.Spot)
+		}
+	}
+	if restored.Shoe.Refill != g.Rules.NumDecks {
+		t.Errorf("shoe refill is %d, expected %d", restored.Shoe.Refill, g.Rules.NumDecks)
+	}
+	if fmt.Sprint(restored.Shoe.Cards) != fmt.Sprint(g.Shoe.Cards) {
+		t.Errorf("shoe is %v, expected %v", restored.Shoe.Cards, g.Shoe.Cards)
+	}
+}
+
+func TestReadRejectsOtherVersions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "save.json")
+	if err := os.WriteFile(path, []byte(`{"version": 2, "game": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Read(path)
+	if err == nil || !strings.Contains(err.Error(), "version 2 is not supported") {
+		t.Errorf("expected a newer version to be refused, got %v", err)
+	}
+}
@@ -0,0 +1,435 @@
+package server
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// SessionView is a session as its client sees it
+// The dealer's hole card stays hidden until the dealer plays, and Actions lists the
+// legal actions for the active hand whenever the session is waiting for one.
+type SessionView struct {
+	ID           string        `json:"id"`
+	Rules        string        `json:"rules"`
+	Phase        game.Phase    `json:"phase"`
+	Bank         int           `json:"bank"`
+	MinBet       int           `json:"min_bet"`
+	MaxBet       int           `json:"max_bet"`
+	Round        int           `json:"round"` // Rounds completed so far
+	Dealer       DealerView    `json:"dealer"`
+	Hands        []HandView    `json:"hands"`
+	Active       int           `json:"active,omitempty"`        // Hand waiting for an action, from 1
+	Actions      []game.Action `json:"actions"`                 // Legal actions for the active hand
+	InsuranceMax []int         `json:"insurance_max,omitempty"` // Largest insurance bet for each spot
+	Surrender    []bool        `json:"surrender,omitempty"`     // Spots that may surrender before the dealer peeks
+	Shoe         int           `json:"shoe"`                    // Undealt cards left in the shoe
+	Result       *RoundOutcome `json:"result,omitempty"`        // How the last round finished
+}
+
+// RoundOutcome reports how a session's last round was settled
+type RoundOutcome struct {
+	Round int               `json:"round"`
+	Net   int               `json:"net"` // Chips won or lost in the round
+	Hands []game.HandResult `json:"hands"`
+}
+
+// API serves game sessions over HTTP as JSON resources
+//
+//	POST   /sessions                  start a session: {"rules", "soft17", "seed"}, all optional
+//	GET    /sessions/{id}             the session's current state
+//	DELETE /sessions/{id}             end the session
+//	POST   /sessions/{id}/bet         start a round: {"bet": 10}, or {"bets": [10, 10]} for several spots
+//	POST   /sessions/{id}/insurance   answer the insurance offer: {"bet": 5}, or {"bets": [...]}; 0 declines
+//	POST   /sessions/{id}/surrender   answer the early surrender offer on several spots: {"surrender": [true, false]}
+//	POST   /sessions/{id}/actions     play the active hand: {"action": "hit"}
+//
+// Every call that succeeds returns the SessionView. Errors are returned as
+// {"error": "..."} with 404 for an unknown session, 409 for a request that isn't
+// allowed in the current phase and 400 for one the engine rejects.
+type API struct {
+	Rules       game.RuleSet  // Rules for sessions that don't pick a preset
+	IdleTimeout time.Duration // Sessions unused for this long are ended; 0 keeps them forever
+	Log         *log.Logger   // Logs sessions starting and ending when not nil
+
+	mux      *http.ServeMux
+	mu       sync.Mutex
+	sessions map[string]*session
+}
+
+// session is one player's game
+type session struct {
+	mu       sync.Mutex
+	id       string
+	game     *game.Game
+	lastUsed time.Time // guarded by API.mu
+}
+
+// NewAPI creates an API whose sessions use the given rules unless they pick others
+func NewAPI(rules game.RuleSet) *API {
+	a := &API{
+		Rules:    rules,
+		mux:      http.NewServeMux(),
+		sessions: make(map[string]*session),
+	}
+	a.mux.HandleFunc("POST /sessions", a.create)
+	a.mux.HandleFunc("GET /sessions/{id}", a.withSession(a.get))
+	a.mux.HandleFunc("DELETE /sessions/{id}", a.end)
+	a.mux.HandleFunc("POST /sessions/{id}/bet", a.withSession(a.bet))
+	a.mux.HandleFunc("POST /sessions/{id}/insurance", a.withSession(a.insurance))
+	a.mux.HandleFunc("POST /sessions/{id}/surrender", a.withSession(a.surrender))
+	a.mux.HandleFunc("POST /sessions/{id}/actions", a.withSession(a.act))
+	return a
+}
+
+func (a *API) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mux.ServeHTTP(w, r)
+}
+
+// apiError is an error with the HTTP status to report it with
+type apiError struct {
+	status int
+	msg    string
+}
+
+func (e *apiError) Error() string {
+	return e.msg
+}
+
+func conflict(format string, args ...any) error {
+	return &apiError{status: http.StatusConflict, msg: fmt.Sprintf(format, args...)}
+}
+
+func badRequest(err error) error {
+	return &apiError{status: http.StatusBadRequest, msg: err.Error()}
+}
+
+// create starts a new session
+func (a *API) create(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Rules  string `json:"rules"`
+		Soft17 string `json:"soft17"`
+		Seed   int64  `json:"seed"`
+	}
+	if err := decode(r, &req); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	rules := a.Rules
+	if req.Rules != "" {
+		var err error
+		if rules, err = game.RulesByName(req.Rules); err != nil {
+			writeError(w, badRequest(err))
+			return
+		}
+	}
+	switch req.Soft17 {
+	case "":
+	case "hit", "h17":
+		rules.DealerHitsSoft17 = true
+	case "stand", "s17":
+		rules.DealerHitsSoft17 = false
+	default:
+		writeError(w, badRequest(fmt.Errorf("invalid soft17 value %q (use \"hit\" or \"stand\")", req.Soft17)))
+		return
+	}
+	seed := req.Seed
+	if seed == 0 {
+		var err error
+		if seed, err = game.NewSeed(); err != nil {
+			a.logf("warning: %v", err)
+		}
+	}
+
+	s := &session{id: newSessionID(), game: game.NewGameWithSeed(rules, seed)}
+	a.mu.Lock()
+	a.expire()
+	s.lastUsed = time.Now()
+	a.sessions[s.id] = s
+	a.mu.Unlock()
+	a.logf("session %s started (%s)", s.id, rules.Name)
+
+	w.Header().Set("Location", "/sessions/"+s.id)
+	writeJSON(w, http.StatusCreated, newSessionView(s))
+}
+
+// end removes a session
+func (a *API) end(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	a.mu.Lock()
+	a.expire()
+	_, ok := a.sessions[id]
+	delete(a.sessions, id)
+	a.mu.Unlock()
+	if !ok {
+		writeError(w, &apiError{status: http.StatusNotFound, msg: "no session " + id})
+		return
+	}
+	a.logf("session %s ended", id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// withSession looks up the session named in the path and runs h holding its lock
+// h returns an error to report, or nil to reply with the session's state. Idle sessions
+// are expired first, so a session that has timed out is not found.
+func (a *API) withSession(h func(r *http.Request, g *game.Game) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := r.PathValue("id")
+		a.mu.Lock()
+		a.expire()
+		s, ok := a.sessions[id]
+		if ok {
+			s.lastUsed = time.Now()
+		}
+		a.mu.Unlock()
+		if !ok {
+			writeError(w, &apiError{status: http.StatusNotFound, msg: "no session " + id})
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err := h(r, s.game); err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, newSessionView(s))
+	}
+}
+
+func (a *API) get(r *http.Request, g *game.Game) error {
+	return nil
+}
+
+func (a *API) bet(r *http.Request, g *game.Game) error {
+	var req struct {
+		Bet  int   `json:"bet"`
+		Bets []int `json:"bets"`
+	}
+	if err := decode(r, &req); err != nil {
+		return err
+	}
+	switch g.CurrentPhase {
+	case game.PhaseBetting:
+	case game.PhaseGameOver:
+		return conflict("game over: the bank is below the minimum bet")
+	default:
+		return conflict("a round is already in progress")
+	}
+
+	bets := req.Bets
+	if len(bets) == 0 {
+		bets = []int{req.Bet}
+	}
+	if err := g.StartHand(bets...); err != nil {
+		return badRequest(err)
+	}
+	return advance(g)
+}
+
+func (a *API) insurance(r *http.Request, g *game.Game) error {
+	var req struct {
+		Bet  int   `json:"bet"`
+		Bets []int `json:"bets"`
+	}
+	if err := decode(r, &req); err != nil {
+		return err
+	}
+	if g.CurrentPhase != game.PhaseInsurance {
+		return conflict("insurance is not being offered")
+	}
+
+	bets := req.Bets
+	if len(bets) == 0 {
+		bets = make([]int, len(g.PlayerHands))
+		bets[0] = req.Bet
+	}
+	total := 0
+	for _, bet := range bets {
+		total += bet
+	}
+
+	var err error
+	if total == 0 && len(bets) == len(g.PlayerHands) {
+		err = g.DeclineInsurance()
+	} else {
+		err = g.TakeInsurance(bets...)
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	return advance(g)
+}
+
+func (a *API) surrender(r *http.Request, g *game.Game) error {
+	var req struct {
+		Surrender []bool `json:"surrender"`
+	}
+	if err := decode(r, &req); err != nil {
+		return err
+	}
+	if !g.EarlySurrenderOffered() {
+		return conflict("early surrender is not being offered")
+	}
+	if err := g.EarlySurrender(req.Surrender...); err != nil {
+		return badRequest(err)
+	}
+	return advance(g)
+}
+
+func (a *API) act(r *http.Request, g *game.Game) error {
+	var req struct {
+		Action game.Action `json:"action"`
+	}
+	if err := decode(r, &req); err != nil {
+		return err
+	}
+	if g.CurrentPhase != game.PhasePlayerAction {
+		return conflict("no hand is waiting for an action")
+	}
+	if surrenderOffered(g) {
+		return conflict("every spot must answer the early surrender offer first")
+	}
+
+	if !game.ContainsAction(g.GetAvailableActions(), req.Action) {
+		return badRequest(fmt.Errorf("%s is not allowed on this hand", req.Action))
+	}
+	if err := g.PlayerAction(req.Action); err != nil {
+		return badRequest(err)
+	}
+	return advance(g)
+}
+
+// advance plays the steps that need no decision, as Game.PlayRound does: settling a round
+// the dealer's peek ended, and standing hands with no action left such as a 21
+func advance(g *game.Game) error {
+	for {
+		switch {
+		case surrenderOffered(g):
+			return nil
+		case g.CurrentPhase == game.PhaseResolution:
+			g.ResolvePayouts()
+		case g.CurrentPhase == game.PhasePlayerAction && len(g.GetAvailableActions()) == 0:
+			if err := g.PlayerAction(game.ActionStand); err != nil {
+				return err
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// surrenderOffered reports whether several spots must answer the early surrender offer
+// together; a single spot answers it with its first action.
+func surrenderOffered(g *game.Game) bool {
+	return g.EarlySurrenderOffered() && len(g.PlayerHands) > 1
+}
+
+// expire ends sessions that have been idle for longer than IdleTimeout; a.mu is held
+func (a *API) expire() {
+	if a.IdleTimeout <= 0 {
+		return
+	}
+	for id, s := range a.sessions {
+		if time.Since(s.lastUsed) > a.IdleTimeout {
+			delete(a.sessions, id)
+			a.logf("session %s expired", id)
+		}
+	}
+}
+
+func (a *API) logf(format string, args ...any) {
+	if a.Log != nil {
+		a.Log.Printf(format, args...)
+	}
+}
+
+// newSessionView returns the state of a session as its client may see it
+func newSessionView(s *session) SessionView {
+	g := s.game
+	view := SessionView{
+		ID:      s.id,
+		Rules:   g.Rules.Name,
+		Phase:   g.CurrentPhase,
+		Bank:    g.Bank,
+		MinBet:  g.Rules.MinBet,
+		MaxBet:  g.MaxBet(),
+		Round:   g.RoundsPlayed,
+		Dealer:  NewDealerView(g.DealerHand, !g.HoleCardRevealed),
+		Hands:   NewHandViews(g.PlayerHands),
+		Actions: g.GetAvailableActions(),
+		Shoe:    g.Shoe.Remaining(),
+	}
+	if view.Actions == nil {
+		view.Actions = []game.Action{}
+	}
+
+	switch {
+	case surrenderOffered(g):
+		view.Actions = []game.Action{}
+		view.Surrender = make([]bool, len(g.PlayerHands))
+		for i, hand := range g.PlayerHands {
+			view.Surrender[i] = hand.CanSurrender(g.Rules)
+		}
+	case g.CurrentPhase == game.PhasePlayerAction:
+		view.Active = g.ActiveHandIndex + 1
+	case g.CurrentPhase == game.PhaseInsurance:
+		// Each spot is offered what the bank can still cover after the earlier spots' offers
+		view.InsuranceMax = make([]int, len(g.PlayerHands))
+		available := g.Bank
+		for i, hand := range g.PlayerHands {
+			max := hand.Bet / 2
+			if max > available {
+				max = available
+			}
+			view.InsuranceMax[i] = max
+			available -= max
+		}
+	case g.CurrentPhase == game.PhaseBetting || g.CurrentPhase == game.PhaseGameOver:
+		if rec := g.LastRound(); rec != nil {
+			view.Result = &RoundOutcome{Round: rec.Round, Net: rec.BankAfter - rec.BankBefore, Hands: rec.Hands}
+		}
+	}
+	return view
+}
+
+// newSessionID returns a random, unguessable session ID
+func newSessionID() string {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("session id: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+// decode reads a request's JSON body into v; an empty body leaves v unchanged
+func decode(r *http.Request, v any) error {
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1<<16))
+	if err := dec.Decode(v); err != nil && err != io.EOF {
+		return badRequest(fmt.Errorf("invalid request body: %v", err))
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if e, ok := err.(*apiError); ok {
+		status = e.status
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// apiClient calls an API served by httptest
+type apiClient struct {
+	t   *testing.T
+	api *API
+	url string
+}
+
+func newAPIClient(t *testing.T) *apiClient {
+	t.Helper()
+	api := NewAPI(game.DefaultRules())
+	srv := httptest.NewServer(api)
+	t.Cleanup(srv.Close)
+	return &apiClient{t: t, api: api, url: srv.URL}
+}
+
+// call makes a request and decodes the reply into v, failing unless the status is as expected
+func (c *apiClient) call(method, path, body string, status int, v any) {
+	c.t.Helper()
+	req, err := http.NewRequest(method, c.url+path, strings.NewReader(body))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var reply json.RawMessage
+	if resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(&reply); err != nil {
+			c.t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+	if resp.StatusCode != status {
+		c.t.Fatalf("%s %s: status %d, expected %d: %s", method, path, resp.StatusCode, status, reply)
+	}
+	if v != nil {
+		if err := json.Unmarshal(reply, v); err != nil {
+			c.t.Fatalf("%s %s: %v", method, path, err)
+		}
+	}
+}
+
+// create starts a session whose shoe deals the given cards in order
+func (c *apiClient) create(cards string) SessionView {
+	c.t.Helper()
+	var view SessionView
+	c.call("POST", "/sessions", `{"seed": 7}`, http.StatusCreated, &view)
+
+	stacked, err := game.ParseCards(cards)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.api.mu.Lock()
+	session := c.api.sessions[view.ID]
+	c.api.mu.Unlock()
+	session.mu.Lock()
+	session.game.Shoe = game.NewStackedShoe(stacked, session.game.RNG)
+	session.mu.Unlock()
+	return view
+}
+
+// post sends a request to one of a session's resources and returns the session's state
+func (c *apiClient) post(id, resource, body string) SessionView {
+	c.t.Helper()
+	var view SessionView
+	c.call("POST", "/sessions/"+id+"/"+resource, body, http.StatusOK, &view)
+	return view
+}
+
+func TestAPIInsuranceRound(t *testing.T) {
+	c := newAPIClient(t)
+	// 10 9 against an ace with a 7 in the hole
+	s := c.create("10S 7C 9D AH")
+	if s.Phase != game.PhaseBetting || s.Bank != game.StartingBank {
+		t.Fatalf("new session is in the %s phase with %d chips", s.Phase, s.Bank)
+	}
+
+	view := c.post(s.ID, "bet", `{"bet": 10}`)
+	if view.Phase != game.PhaseInsurance {
+		t.Fatalf("phase is %s, expected insurance", view.Phase)
+	}
+	if fmt.Sprint(view.InsuranceMax) != "[5]" {
+		t.Errorf("insurance max is %v, expected [5]", view.InsuranceMax)
+	}
+
+	view = c.post(s.ID, "insurance", `{"bet": 5}`)
+	if view.Phase != game.PhasePlayerAction || view.Active != 1 {
+		t.Fatalf("phase is %s with hand %d active, expected hand 1 to act", view.Phase, view.Active)
+	}
+	if view.Hands[0].Insurance != 5 {
+		t.Errorf("insurance is %d, expected 5", view.Hands[0].Insurance)
+	}
+
+	view = c.post(s.ID, "actions", `{"action": "stand"}`)
+	if view.Phase != game.PhaseBetting {
+		t.Fatalf("phase is %s, expected betting", view.Phase)
+	}
+	// The insurance is lost and the 19 beats the dealer's soft 18
+	if view.Bank != 1005 || view.Result == nil || view.Result.Net != 5 {
+		t.Errorf("bank is %d with result %+v, expected 1005 and a net of 5", view.Bank, view.Result)
+	}
+	if fmt.Sprint(view.Dealer.Cards) != "[7♣ A♥]" || view.Dealer.HoleHidden {
+		t.Errorf("dealer shows %v (hidden %v), expected the hole card turned over", view.Dealer.Cards, view.Dealer.HoleHidden)
+	}
+}
+
+func TestAPIInsuranceMaxFitsBank(t *testing.T) {
+	c := newAPIClient(t)
+	// 10 9 and 9 8 against an ace, with 100 chips left once the bets are down
+	s := c.create("10S 9C 7H 9D 8S AH")
+
+	view := c.post(s.ID, "bet", `{"bets": [450, 450]}`)
+	if fmt.Sprint(view.InsuranceMax) != "[100 0]" {
+		t.Fatalf("insurance max is %v, expected [100 0]", view.InsuranceMax)
+	}
+	view = c.post(s.ID, "insurance", `{"bets": [100, 0]}`)
+	if view.Hands[0].Insurance != 100 || view.Bank != 0 {
+		t.Errorf("insurance is %d with %d chips left, expected 100 and 0", view.Hands[0].Insurance, view.Bank)
+	}
+}
+
+func TestAPIHidesHoleCard(t *testing.T) {
+	c := newAPIClient(t)
+	s := c.create("10S 7C 9D 6H")
+
+	var raw map[string]any
+	c.call("POST", "/sessions/"+s.ID+"/bet", `{"bet": 10}`, http.StatusOK, &raw)
+	data, err := json.Marshal(raw["dealer"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "7C") {
+		t.Errorf("the dealer's hole card is in the view: %s", data)
+	}
+
+	var view SessionView
+	c.call("GET", "/sessions/"+s.ID, "", http.StatusOK, &view)
+	if !view.Dealer.HoleHidden || len(view.Dealer.Cards) != 1 || view.Dealer.Value != 0 {
+		t.Errorf("dealer view is %+v, expected only the upcard", view.Dealer)
+	}
+}
+
+func TestAPIActions(t *testing.T) {
+	tests := []struct {
+		name    string
+		cards   string
+		actions []string
+		bank    int
+	}{
+		// 5 6 against a 7 with a 10 in the hole; the dealer stands on 17
+		{"hit", "5S 10C 6D 7H 9C", []string{"hit", "stand"}, 1010},
+		{"stand", "10S 10C 9D 7H", []string{"stand"}, 1010},
+		{"double", "5S 10C 6D 7H 9C", []string{"double"}, 1020},
+		// 8 3 and 8 2 both lose to 17
+		{"split", "8S 10C 8D 7H 3C 2D", []string{"split", "stand", "stand"}, 980},
+		{"surrender", "10S 10C 6D 7H", []string{"surrender"}, 995},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newAPIClient(t)
+			s := c.create(tt.cards)
+			view := c.post(s.ID, "bet", `{"bet": 10}`)
+			for _, action := range tt.actions {
+				if view.Phase != game.PhasePlayerAction {
+					t.Fatalf("phase is %s before %s", view.Phase, action)
+				}
+				view = c.post(s.ID, "actions", fmt.Sprintf(`{"action": %q}`, action))
+			}
+			if view.Phase != game.PhaseBetting {
+				t.Fatalf("phase is %s, expected the round to be over", view.Phase)
+			}
+			if view.Bank != tt.bank {
+				t.Errorf("bank is %d, expected %d", view.Bank, tt.bank)
+			}
+		})
+	}
+}
+
+func TestAPIEarlySurrender(t *testing.T) {
+	c := newAPIClient(t)
+	c.api.Rules.Surrender = game.SurrenderEarly
+	// 10 6 and 9 7 against a king with an ace in the hole
+	s := c.create("10S 9C AH 6D 7H KD")
+
+	view := c.post(s.ID, "bet", `{"bets": [10, 10]}`)
+	if fmt.Sprint(view.Surrender) != "[true true]" || len(view.Actions) > 0 {
+		t.Fatalf("surrender is offered on %v with actions %v, expected both spots and no actions", view.Surrender, view.Actions)
+	}
+	c.call("POST", "/sessions/"+s.ID+"/actions", `{"action": "hit"}`, http.StatusConflict, nil)
+	c.call("POST", "/sessions/"+s.ID+"/surrender", `{"surrender": [true]}`, http.StatusBadRequest, nil)
+
+	// The second spot surrenders before the peek finds blackjack
+	view = c.post(s.ID, "surrender", `{"surrender": [false, true]}`)
+	if view.Phase != game.PhaseBetting {
+		t.Fatalf("phase is %s, expected the round to be over", view.Phase)
+	}
+	if view.Bank != 985 {
+		t.Errorf("bank is %d, expected 985", view.Bank)
+	}
+	c.call("POST", "/sessions/"+s.ID+"/surrender", `{"surrender": [false, false]}`, http.StatusConflict, nil)
+}
+
+func TestAPIErrors(t *testing.T) {
+	c := newAPIClient(t)
+	c.call("GET", "/sessions/missing", "", http.StatusNotFound, nil)
+	c.call("POST", "/sessions/missing/bet", `{"bet": 10}`, http.StatusNotFound, nil)
+	c.call("DELETE", "/sessions/missing", "", http.StatusNotFound, nil)
+
+	s := c.create("10S 10C 9D 7H")
+	c.call("POST", "/sessions/"+s.ID+"/actions", `{"action": "hit"}`, http.StatusConflict, nil)
+	c.call("POST", "/sessions/"+s.ID+"/insurance", `{"bet": 5}`, http.StatusConflict, nil)
+	c.post(s.ID, "bet", `{"bet": 10}`)
+	c.call("POST", "/sessions/"+s.ID+"/bet", `{"bet": 10}`, http.StatusConflict, nil)
+	c.call("POST", "/sessions/"+s.ID+"/actions", `{"action": "split"}`, http.StatusBadRequest, nil)
+
+	c.call("DELETE", "/sessions/"+s.ID, "", http.StatusNoContent, nil)
+	c.call("GET", "/sessions/"+s.ID, "", http.StatusNotFound, nil)
+}
+
+func TestAPIExpiresIdleSessions(t *testing.T) {
+	c := newAPIClient(t)
+	c.api.IdleTimeout = time.Hour
+	s := c.create("10S 10C 9D 7H")
+	c.call("GET", "/sessions/"+s.ID, "", http.StatusOK, nil)
+
+	// The session was last used longer ago than the timeout; using it now finds it gone
+	c.api.mu.Lock()
+	c.api.sessions[s.ID].lastUsed = time.Now().Add(-2 * time.Hour)
+	c.api.mu.Unlock()
+	c.call("GET", "/sessions/"+s.ID, "", http.StatusNotFound, nil)
+}
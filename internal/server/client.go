@@ -0,0 +1,258 @@
+package server
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// errNoAnswer is returned when a prompt times out or the client goes away
+var errNoAnswer = errors.New("no answer")
+
+// command is one line from a client, split into its keyword and argument
+type command struct {
+	verb string
+	arg  string
+}
+
+// client is one connection to the table server
+// Its reader goroutine handles commands as they arrive. While the client holds a seat it
+// is also the seat's Player: those methods run on the dealer goroutine and wait for the
+// client's answer to each prompt.
+type client struct {
+	server  *TableServer
+	conn    net.Conn
+	out     chan string
+	answers chan command
+	gone    chan struct{}
+
+	closeOnce sync.Once
+	mu        sync.Mutex
+	awaiting  string // Keyword of the prompt waiting for an answer, "" when none
+
+	name string // Name the client joined with, "" when not seated; guarded by server.mu
+}
+
+func newClient(s *TableServer, conn net.Conn) *client {
+	c := &client{
+		server:  s,
+		conn:    conn,
+		out:     make(chan string, 256),
+		answers: make(chan command, 1),
+		gone:    make(chan struct{}),
+	}
+	go c.write()
+	return c
+}
+
+// read handles the client's commands until the connection closes
+func (c *client) read() {
+	defer c.close()
+
+	scanner := bufio.NewScanner(c.conn)
+	for scanner.Scan() {
+		verb, arg, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		cmd := command{verb: strings.ToUpper(verb), arg: strings.TrimSpace(arg)}
+
+		switch cmd.verb {
+		case "":
+		case "JOIN":
+			c.server.join(c, cmd.arg)
+		case "STATE":
+			c.send(c.server.state())
+		case "QUIT":
+			c.send("BYE")
+			return
+		case "BET", "LEAVE", "INSURANCE", "ACT":
+			c.answer(cmd)
+		default:
+			c.send("ERROR unknown command " + verb)
+		}
+	}
+}
+
+// write sends queued lines to the connection, then closes it once the client is gone
+func (c *client) write() {
+	defer c.conn.Close()
+	w := bufio.NewWriter(c.conn)
+	for {
+		select {
+		case line := <-c.out:
+			fmt.Fprintln(w, line)
+			if len(c.out) == 0 {
+				if err := w.Flush(); err != nil {
+					c.close()
+				}
+			}
+		case <-c.gone:
+			// Flush what was queued before the close, such as a final BYE
+			for {
+				select {
+				case line := <-c.out:
+					fmt.Fprintln(w, line)
+				default:
+					w.Flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// send queues a line for the client, dropping a client too slow to keep up
+func (c *client) send(line string) {
+	select {
+	case c.out <- line:
+	case <-c.gone:
+	default:
+		c.close()
+	}
+}
+
+// close ends the connection
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.gone)
+		c.server.remove(c)
+	})
+}
+
+// closed reports whether the connection has ended
+func (c *client) closed() bool {
+	select {
+	case <-c.gone:
+		return true
+	default:
+		return false
+	}
+}
+
+// answer passes the client's answer to the prompt waiting for it
+func (c *client) answer(cmd command) {
+	c.mu.Lock()
+	awaiting := c.awaiting
+	c.mu.Unlock()
+
+	if awaiting == "" || (cmd.verb != awaiting && !(cmd.verb == "LEAVE" && awaiting == "BET")) {
+		c.send("ERROR not waiting for " + cmd.verb)
+		return
+	}
+	select {
+	case c.answers <- cmd:
+	default:
+		c.send("ERROR already answered")
+	}
+}
+
+// ask publishes the table, sends a prompt and waits for the client's answer
+func (c *client) ask(g *game.Game, verb string, prompt string) (command, error) {
+	// Drop an answer left over from a prompt that timed out
+	select {
+	case <-c.answers:
+	default:
+	}
+
+	c.server.publish(strings.ToLower(verb), g)
+	c.mu.Lock()
+	c.awaiting = verb
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.awaiting = ""
+		c.mu.Unlock()
+	}()
+	c.send(prompt)
+
+	var timeout <-chan time.Time
+	if c.server.Timeout > 0 {
+		timer := time.NewTimer(c.server.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case cmd := <-c.answers:
+		return cmd, nil
+	case <-timeout:
+		c.send("ERROR timed out")
+		return command{}, errNoAnswer
+	case <-c.gone:
+		return command{}, errNoAnswer
+	case <-c.server.done:
+		return command{}, errNoAnswer
+	}
+}
+
+func (c *client) Bet(g *game.Game) (int, error) {
+	for {
+		cmd, err := c.ask(g, "BET", fmt.Sprintf("BET %d %d", g.Rules.MinBet, g.MaxBet()))
+		if err != nil {
+			c.server.leave(c, "timeout")
+			return 0, game.ErrQuit
+		}
+		if cmd.verb == "LEAVE" {
+			c.server.leave(c, "left")
+			return 0, game.ErrQuit
+		}
+
+		bet, err := strconv.Atoi(cmd.arg)
+		if err != nil || bet < g.Rules.MinBet || bet > g.MaxBet() {
+			c.send(fmt.Sprintf("ERROR bet must be %d-%d", g.Rules.MinBet, g.MaxBet()))
+			continue
+		}
+		return bet, nil
+	}
+}
+
+func (c *client) Insurance(g *game.Game, max int) (int, error) {
+	for {
+		cmd, err := c.ask(g, "INSURANCE", fmt.Sprintf("INSURANCE %d", max))
+		if err != nil {
+			return 0, nil
+		}
+
+		bet, err := strconv.Atoi(cmd.arg)
+		if err != nil || bet < 0 || bet > max {
+			c.send(fmt.Sprintf("ERROR insurance must be 0-%d", max))
+			continue
+		}
+		return bet, nil
+	}
+}
+
+func (c *client) Act(g *game.Game, hand *game.Hand, available []game.Action) (game.Action, error) {
+	names := make([]string, len(available))
+	for i, action := range available {
+		names[i] = strings.ToLower(action.String())
+	}
+	prompt := fmt.Sprintf("ACT %d %s", g.ActiveHandIndex+1, strings.Join(names, ","))
+
+	for {
+		cmd, err := c.ask(g, "ACT", prompt)
+		if err != nil {
+			return standOr(available), nil
+		}
+
+		action, err := game.ParseAction(cmd.arg)
+		if err != nil || !game.ContainsAction(available, action) {
+			c.send("ERROR action must be one of " + strings.Join(names, ","))
+			continue
+		}
+		return action, nil
+	}
+}
+
+// standOr returns Stand if it is available, or else the first available action
+func standOr(available []game.Action) game.Action {
+	if game.ContainsAction(available, game.ActionStand) {
+		return game.ActionStand
+	}
+	return available[0]
+}
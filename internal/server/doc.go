@@ -0,0 +1,43 @@
+// Package server hosts blackjack games for clients on the network
+//
+// TableServer is also an http.Handler for a WebSocket feed of the table: every engine
+// change is pushed as a LiveMessage as it happens, and a client that connects or
+// reconnects starts from the latest snapshot. websocket.go implements the small part of
+// RFC 6455 the feed needs.
+//
+// API serves single-player sessions over HTTP as JSON resources, for web and mobile
+// clients that want the engine's rules without reimplementing them; see API for the
+// routes.
+//
+// TableServer hosts one shared table over TCP. Clients speak a line protocol: every
+// message is one line of text, a keyword followed by its arguments and separated by
+// spaces. Snapshots and results carry a single line of JSON.
+//
+// Client to server:
+//
+//	JOIN <name>           take the next free seat when the next round starts
+//	BET <chips>           answer a BET prompt
+//	LEAVE                 answer a BET prompt by leaving the seat
+//	INSURANCE <chips>     answer an INSURANCE prompt; 0 declines
+//	ACT <action>          answer an ACT prompt: hit, stand, double, split or surrender
+//	STATE                 ask for the latest snapshot
+//	QUIT                  close the connection
+//
+// Server to client:
+//
+//	HELLO blackjack <version>      sent on connect
+//	WAIT                           JOIN accepted; the seat is taken when the next round starts
+//	WELCOME <seat> <bank>          seated, with the seat number from 1
+//	STATE <json>                   a TableSnapshot, sent to everyone before every decision
+//	BET <min> <max>                your bet for the next round
+//	INSURANCE <max>                the dealer shows an Ace; your insurance bet
+//	ACT <hand> <action,...>        your action for the hand (numbered from 1)
+//	RESULT <json>                  a RoundResult, sent to everyone after every round
+//	LEFT <reason>                  you no longer have a seat: left, busted, timeout
+//	ERROR <message>                the last line was not understood or not allowed
+//	BYE                            the connection is closing
+//
+// Clients that don't JOIN watch the table. A prompt that isn't answered within the
+// server's timeout, or whose connection drops, is answered for the player: a bet leaves
+// the seat, insurance is declined and the hand stands.
+package server
@@ -0,0 +1,164 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// LiveMessage is one message pushed to the table's WebSocket clients
+// A "state" message carries a TableSnapshot, an "event" message one change made by the
+// engine (a card dealt, an action, a payout and so on) and a "result" message a
+// RoundResult. Seq numbers every message in order so a client can tell when it has
+// seen one before.
+type LiveMessage struct {
+	Seq    int64          `json:"seq"`
+	Type   string         `json:"type"`
+	State  *TableSnapshot `json:"state,omitempty"`
+	Result *RoundResult   `json:"result,omitempty"`
+	Event  string         `json:"event,omitempty"` // Kind of event, such as "card_dealt"
+	Seat   int            `json:"seat,omitempty"`  // Seat the event happened at, from 1; 0 for the dealer or the shoe
+	Data   map[string]any `json:"data,omitempty"`
+}
+
+// spectator is a WebSocket client watching the table
+type spectator struct {
+	ws        *wsConn
+	out       chan []byte
+	gone      chan struct{}
+	closeOnce sync.Once
+}
+
+// ServeHTTP upgrades the request to a WebSocket and pushes the table to it as it changes
+// The client first gets the latest snapshot and every message since, so a client that
+// reconnects is always brought back in sync, and then each message as it happens.
+// Any number of clients can watch at once; anything they send other than a close is
+// ignored.
+func (s *TableServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ws, err := upgradeWebSocket(w, r)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	sp := &spectator{
+		ws:   ws,
+		out:  make(chan []byte, len(s.backlog)+256),
+		gone: make(chan struct{}),
+	}
+	for _, data := range s.backlog {
+		sp.out <- data
+	}
+	s.spectators[sp] = true
+	s.mu.Unlock()
+
+	go sp.write()
+	for {
+		if _, err := ws.ReadMessage(); err != nil {
+			break
+		}
+	}
+	sp.close()
+
+	s.mu.Lock()
+	delete(s.spectators, sp)
+	s.mu.Unlock()
+}
+
+// write sends queued messages until the spectator goes away
+func (sp *spectator) write() {
+	for {
+		select {
+		case data := <-sp.out:
+			if err := sp.ws.WriteText(data); err != nil {
+				sp.close()
+				return
+			}
+		case <-sp.gone:
+			return
+		}
+	}
+}
+
+// send queues a message, dropping a spectator too slow to keep up
+func (sp *spectator) send(data []byte) {
+	select {
+	case sp.out <- data:
+	default:
+		sp.close()
+	}
+}
+
+// close drops the connection; it may be called with the server locked, so it never
+// waits on the network
+func (sp *spectator) close() {
+	sp.closeOnce.Do(func() {
+		close(sp.gone)
+		sp.ws.conn.Close()
+	})
+}
+
+// pushLive numbers a message and sends it to every spectator
+// A state message starts the backlog over, since it holds everything before it.
+func (s *TableServer) pushLive(msg LiveMessage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+	msg.Seq = s.seq
+	data := []byte(mustJSON(msg))
+	if msg.Type == "state" {
+		s.backlog = s.backlog[:0:0]
+	}
+	s.backlog = append(s.backlog, data)
+	for sp := range s.spectators {
+		sp.send(data)
+	}
+}
+
+// pushEvent sends an engine event to the spectators; it runs on the dealer goroutine
+func (s *TableServer) pushEvent(seat int, e game.Event) {
+	name, data := liveEvent(e)
+	msg := LiveMessage{Type: "event", Event: name, Data: data}
+	// The table gives -1 for the dealer's cards and the shoe; the hole card is the dealer's too
+	if _, hole := e.(game.HoleCardRevealed); seat >= 0 && !hole {
+		msg.Seat = seat + 1
+	}
+	s.pushLive(msg)
+}
+
+// liveEvent returns the name and data of an event as pushed to spectators
+// Hands are numbered from 1, as in the line protocol, and the hole card is left out
+// until the dealer turns it over.
+func liveEvent(e game.Event) (string, map[string]any) {
+	switch e := e.(type) {
+	case game.CardDealt:
+		data := map[string]any{"dealer": e.Dealer}
+		if !e.Dealer {
+			data["hand"] = e.Hand + 1
+		}
+		if e.FaceDown {
+			data["face_down"] = true
+		} else {
+			data["card"] = e.Card
+		}
+		return "card_dealt", data
+	case game.HoleCardRevealed:
+		return "hole_card_revealed", map[string]any{"card": e.Card}
+	case game.ActionTaken:
+		return "action_taken", map[string]any{"hand": e.Hand + 1, "action": e.Action, "value": e.Value, "bust": e.Bust}
+	case game.HandSplit:
+		return "hand_split", map[string]any{"hand": e.Hand + 1, "new_hand": e.NewHand + 1}
+	case game.InsuranceResolved:
+		return "insurance_resolved", map[string]any{"hand": e.Hand + 1, "bet": e.Bet, "won": e.Won, "payout": e.Payout}
+	case game.HandResolved:
+		return "hand_resolved", map[string]any{"hand": e.Hand + 1, "outcome": e.Outcome, "bet": e.Bet, "payout": e.Payout}
+	case game.ShoeShuffled:
+		return "shoe_shuffled", map[string]any{"emergency": e.Emergency}
+	case game.BankChanged:
+		return "bank_changed", map[string]any{"kind": e.Kind, "spot": e.Spot + 1, "split_order": e.SplitOrder, "amount": e.Amount, "balance": e.Balance}
+	default:
+		return "unknown", nil
+	}
+}
@@ -0,0 +1,209 @@
+package server
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startLiveTableServer starts a table server with its WebSocket feed on an HTTP test server
+// It returns the TCP address players join at and the feed's URL.
+func startLiveTableServer(t *testing.T) (string, string) {
+	t.Helper()
+	srv, addr := serveTable(t)
+	web := httptest.NewServer(srv)
+	t.Cleanup(web.Close)
+	return addr, "ws" + strings.TrimPrefix(web.URL, "http") + "/table/live"
+}
+
+// liveClient is a spectator reading the feed, as a browser would
+type liveClient struct {
+	ws   *wsConn
+	last int64 // Seq of the last message read
+}
+
+func dialLive(t *testing.T, url string) *liveClient {
+	t.Helper()
+	host := strings.TrimPrefix(url, "ws://")
+	host, path, _ := strings.Cut(host, "/")
+	conn, err := net.Dial("tcp", host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	raw := make([]byte, 16)
+	rand.Read(raw)
+	key := base64.StdEncoding.EncodeToString(raw)
+	fmt.Fprintf(conn, "GET /%s HTTP/1.1\r\nHost: %s\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\nSec-WebSocket-Version: 13\r\n\r\n", path, host, key)
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		t.Fatalf("handshake failed: %s %v", resp.Status, resp.Header)
+	}
+	return &liveClient{ws: &wsConn{conn: conn, br: br, client: true}}
+}
+
+// next reads the next message, checking that none was skipped since the last one
+func (c *liveClient) next(t *testing.T) (LiveMessage, []byte) {
+	t.Helper()
+	c.ws.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	data, err := c.ws.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msg LiveMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		t.Fatal(err)
+	}
+	if c.last != 0 && msg.Seq != c.last+1 {
+		t.Fatalf("message %d followed message %d", msg.Seq, c.last)
+	}
+	c.last = msg.Seq
+	return msg, data
+}
+
+func TestLiveFeedPushesEveryChange(t *testing.T) {
+	addr, url := startLiveTableServer(t)
+	watcher := dialLive(t, url)
+	if msg, _ := watcher.next(t); msg.Type != "state" {
+		t.Fatalf("expected a snapshot first, got %q", msg.Type)
+	}
+
+	player := dial(t, addr)
+	done := make(chan error, 1)
+	go func() {
+		_, err := player.play("ann", 1, 2)
+		done <- err
+	}()
+
+	seen := map[string]int{}
+	holeDealt, holeShown := false, false
+	for results := 0; results < 2; {
+		msg, _ := watcher.next(t)
+		switch msg.Type {
+		case "result":
+			results++
+			if !holeShown {
+				t.Fatal("the round ended without the hole card being turned over")
+			}
+			holeDealt, holeShown = false, false
+		case "state":
+			if msg.State.Prompt == "act" && !msg.State.Dealer.HoleHidden {
+				t.Fatal("snapshot shows the hole card while the player acts")
+			}
+		case "event":
+			seen[msg.Event]++
+			switch msg.Event {
+			case "card_dealt":
+				if msg.Data["face_down"] == true {
+					holeDealt = true
+					if _, ok := msg.Data["card"]; ok || msg.Seat != 0 {
+						t.Fatalf("hole card pushed face up: %+v", msg)
+					}
+				}
+			case "hole_card_revealed":
+				if !holeDealt {
+					t.Fatal("hole card revealed before it was dealt")
+				}
+				holeShown = true
+			case "action_taken", "hand_resolved", "bank_changed":
+				if msg.Seat != 1 {
+					t.Errorf("%s pushed for seat %d, expected 1", msg.Event, msg.Seat)
+				}
+			}
+		}
+	}
+
+	for _, event := range []string{"card_dealt", "hole_card_revealed", "hand_resolved", "bank_changed"} {
+		if seen[event] == 0 {
+			t.Errorf("no %s event was pushed", event)
+		}
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLiveFeedResyncsOnReconnect(t *testing.T) {
+	addr, url := startLiveTableServer(t)
+	watcher := dialLive(t, url)
+	dropper := dialLive(t, url)
+
+	// Play up to the first decision of a round
+	player := dial(t, addr)
+	player.send("JOIN ann")
+	for {
+		verb, _, err := player.next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if verb == "BET" {
+			player.send("BET 10")
+		} else if verb == "INSURANCE" {
+			player.send("INSURANCE 0")
+		} else if verb == "ACT" {
+			break
+		}
+	}
+
+	// The spectator that reconnects mid-round starts from the latest snapshot
+	dropper.ws.conn.Close()
+	rejoined := dialLive(t, url)
+	first, firstData := rejoined.next(t)
+	if first.Type != "state" || first.State.Prompt != "act" || !first.State.Dealer.HoleHidden {
+		t.Fatalf("expected the snapshot of the act prompt, got %+v", first)
+	}
+
+	player.send("ACT stand")
+	go func() {
+		// Keep answering so the round can finish however it goes
+		for {
+			verb, _, err := player.next()
+			if err != nil {
+				return
+			}
+			switch verb {
+			case "ACT":
+				player.send("ACT stand")
+			case "BET":
+				player.send("LEAVE")
+			}
+		}
+	}()
+
+	// Both spectators see the same messages, whenever they connected
+	pushed := map[int64][]byte{}
+	for {
+		msg, data := watcher.next(t)
+		pushed[msg.Seq] = data
+		if msg.Type == "result" {
+			break
+		}
+	}
+	if string(pushed[first.Seq]) != string(firstData) {
+		t.Fatalf("reconnected to a snapshot the other spectator never saw: %s", firstData)
+	}
+	for {
+		msg, data := rejoined.next(t)
+		if want, ok := pushed[msg.Seq]; !ok || string(want) != string(data) {
+			t.Fatalf("message %d differs between spectators:\n%s\n%s", msg.Seq, data, want)
+		}
+		if msg.Type == "result" {
+			break
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// ProtocolVersion is the version of the line protocol announced in HELLO
+const ProtocolVersion = 1
+
+// TableSnapshot is the state of the table sent to every client
+type TableSnapshot struct {
+	Round  int        `json:"round"`            // Rounds completed so far
+	Prompt string     `json:"prompt,omitempty"` // Decision awaited: "bet", "insurance" or "act"
+	Turn   int        `json:"turn,omitempty"`   // Seat whose decision is awaited, from 1
+	Active int        `json:"active,omitempty"` // Hand being played by that seat, from 1
+	Dealer DealerView `json:"dealer"`
+	Seats  []SeatView `json:"seats"`
+	Shoe   int        `json:"shoe"` // Undealt cards left in the shoe
+}
+
+// SeatView is an occupied seat as every client sees it
+type SeatView struct {
+	Seat  int        `json:"seat"`
+	Name  string     `json:"name"`
+	Bank  int        `json:"bank"`
+	Hands []HandView `json:"hands"`
+}
+
+// RoundResult reports how every seat in a round did
+type RoundResult struct {
+	Round  int          `json:"round"`
+	Dealer DealerView   `json:"dealer"`
+	Seats  []SeatResult `json:"seats"`
+}
+
+// SeatResult is one seat's part of a RoundResult
+type SeatResult struct {
+	Seat  int               `json:"seat"`
+	Name  string            `json:"name"`
+	Bank  int               `json:"bank"`
+	Net   int               `json:"net"` // Chips won or lost in the round
+	Hands []game.HandResult `json:"hands"`
+}
+
+// TableServer hosts one shared table for clients speaking the line protocol over TCP
+// The table is only ever touched by the dealer goroutine, which plays round after round
+// while anyone is seated; each connection has its own goroutine reading its commands.
+type TableServer struct {
+	Timeout time.Duration // How long a player has for each decision; 0 waits forever
+	Log     *log.Logger   // Logs joins, leaves and errors when not nil
+
+	table *game.Table
+	round int
+
+	mu       sync.Mutex
+	clients  map[*client]bool
+	pending  []*client // Clients waiting for a seat
+	snapshot string    // Latest STATE line
+
+	seq        int64               // Number of the last live message
+	backlog    [][]byte            // Live messages since the latest snapshot, starting with it
+	spectators map[*spectator]bool // WebSocket clients watching the table
+
+	wake      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	listener  net.Listener
+}
+
+// NewTableServer creates a server for a table with the given rules, shuffled from seed
+func NewTableServer(rules game.RuleSet, seed int64) *TableServer {
+	s := &TableServer{
+		table:      game.NewTable(rules, seed),
+		clients:    make(map[*client]bool),
+		spectators: make(map[*spectator]bool),
+		wake:       make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	s.table.Subscribe(s.pushEvent)
+	s.publish("", nil)
+	return s
+}
+
+// Serve accepts connections on the listener and plays the table until Close is called
+func (s *TableServer) Serve(l net.Listener) error {
+	s.mu.Lock()
+	s.listener = l
+	s.mu.Unlock()
+
+	go s.run()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			select {
+			case <-s.done:
+				return nil
+			default:
+				return err
+			}
+		}
+		c := newClient(s, conn)
+		s.mu.Lock()
+		s.clients[c] = true
+		s.mu.Unlock()
+		c.send(fmt.Sprintf("HELLO blackjack %d", ProtocolVersion))
+		go c.read()
+	}
+}
+
+// Close stops the server and disconnects every client
+func (s *TableServer) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.mu.Lock()
+		if s.listener != nil {
+			err = s.listener.Close()
+		}
+		clients := make([]*client, 0, len(s.clients))
+		for c := range s.clients {
+			clients = append(clients, c)
+		}
+		for sp := range s.spectators {
+			sp.close()
+		}
+		s.mu.Unlock()
+		for _, c := range clients {
+			c.send("BYE")
+			c.close()
+		}
+	})
+	return err
+}
+
+// run is the dealer goroutine: it seats new players and plays rounds while anyone is seated
+func (s *TableServer) run() {
+	for {
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+
+		s.seatPending()
+		if len(s.table.Playing()) == 0 {
+			select {
+			case <-s.wake:
+			case <-s.done:
+				return
+			}
+			continue
+		}
+
+		err := s.table.PlayRound()
+		if errors.Is(err, game.ErrQuit) {
+			s.publish("", nil)
+			continue
+		}
+		if err != nil {
+			s.logf("round abandoned: %v", err)
+			continue
+		}
+		s.round++
+		s.finishRound()
+	}
+}
+
+// join queues a client for a seat at the start of the next round
+func (s *TableServer) join(c *client, name string) {
+	if name == "" {
+		c.send("ERROR JOIN needs a name")
+		return
+	}
+
+	s.mu.Lock()
+	if c.name != "" {
+		s.mu.Unlock()
+		c.send("ERROR already joined as " + c.name)
+		return
+	}
+	c.name = name
+	s.pending = append(s.pending, c)
+	s.mu.Unlock()
+
+	c.send("WAIT")
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// seatPending gives each waiting client the next free seat
+func (s *TableServer) seatPending() {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	for _, c := range pending {
+		if c.closed() {
+			continue
+		}
+		seat, err := s.table.Sit(c.name)
+		if err != nil {
+			s.mu.Lock()
+			c.name = ""
+			s.mu.Unlock()
+			c.send("ERROR " + err.Error())
+			continue
+		}
+		seat.Player = c
+		c.send(fmt.Sprintf("WELCOME %d %d", s.seatNumber(seat.Game), seat.Game.Bank))
+		s.logf("%s sat down in seat %d", c.name, s.seatNumber(seat.Game))
+	}
+	if len(pending) > 0 {
+		s.publish("", nil)
+	}
+}
+
+// leave frees a client's seat name so it can join again
+func (s *TableServer) leave(c *client, reason string) {
+	s.mu.Lock()
+	name := c.name
+	c.name = ""
+	s.mu.Unlock()
+	c.send("LEFT " + reason)
+	s.logf("%s left the table (%s)", name, reason)
+}
+
+// remove forgets a closed connection
+func (s *TableServer) remove(c *client) {
+	s.mu.Lock()
+	delete(s.clients, c)
+	s.mu.Unlock()
+}
+
+// finishRound sends every client the round's results and the final table
+func (s *TableServer) finishRound() {
+	result := RoundResult{
+		Round:  s.round,
+		Dealer: NewDealerView(s.table.DealerHand, false),
+		Seats:  []SeatResult{},
+	}
+	for i, seat := range s.table.Seats {
+		rec := seat.Game.LastRound()
+		if seat.Left || len(seat.Game.PlayerHands) == 0 || rec == nil {
+			continue
+		}
+		result.Seats = append(result.Seats, SeatResult{
+			Seat:  i + 1,
+			Name:  seat.Name,
+			Bank:  seat.Game.Bank,
+			Net:   rec.BankAfter - rec.BankBefore,
+			Hands: rec.Hands,
+		})
+		if err := seat.Game.CheckBank(); err != nil {
+			s.logf("seat %d: %v", i+1, err)
+		}
+	}
+	s.broadcast("RESULT " + mustJSON(result))
+	s.pushLive(LiveMessage{Type: "result", Result: &result})
+	s.publish("", nil)
+
+	for _, seat := range s.table.Seats {
+		if c, ok := seat.Player.(*client); ok && !seat.Left && seat.Game.CurrentPhase == game.PhaseGameOver {
+			s.leave(c, "busted")
+		}
+	}
+}
+
+// publish records a snapshot of the table and sends it to every client
+// prompt and g name the decision about to be asked for, if any.
+func (s *TableServer) publish(prompt string, g *game.Game) {
+	snap := TableSnapshot{
+		Round:  s.round,
+		Prompt: prompt,
+		Dealer: NewDealerView(s.table.DealerHand, !s.table.HoleCardRevealed),
+		Seats:  []SeatView{},
+		Shoe:   s.table.Shoe.Remaining(),
+	}
+	if g != nil {
+		snap.Turn = s.seatNumber(g)
+		if prompt == "act" || prompt == "insurance" {
+			snap.Active = g.ActiveHandIndex + 1
+		}
+	}
+	for i, seat := range s.table.Seats {
+		if seat.Left {
+			continue
+		}
+		snap.Seats = append(snap.Seats, SeatView{
+			Seat:  i + 1,
+			Name:  seat.Name,
+			Bank:  seat.Game.Bank,
+			Hands: NewHandViews(seat.Game.PlayerHands),
+		})
+	}
+
+	line := "STATE " + mustJSON(snap)
+	s.mu.Lock()
+	s.snapshot = line
+	s.mu.Unlock()
+	s.broadcast(line)
+	s.pushLive(LiveMessage{Type: "state", State: &snap})
+}
+
+// state returns the latest STATE line
+func (s *TableServer) state() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot
+}
+
+// broadcast sends a line to every connected client
+func (s *TableServer) broadcast(line string) {
+	s.mu.Lock()
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mu.Unlock()
+	for _, c := range clients {
+		c.send(line)
+	}
+}
+
+// seatNumber returns the number, from 1, of the seat playing the game
+func (s *TableServer) seatNumber(g *game.Game) int {
+	for i, seat := range s.table.Seats {
+		if seat.Game == g {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func (s *TableServer) logf(format string, args ...any) {
+	if s.Log != nil {
+		s.Log.Printf(format, args...)
+	}
+}
+
+// mustJSON encodes a protocol message, which can't fail for the types used here
+func mustJSON(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(fmt.Sprintf("encode %T: %v", v, err))
+	}
+	return string(data)
+}
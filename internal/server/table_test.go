@@ -0,0 +1,314 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// testClient drives the table server over the line protocol, as a remote player would
+type testClient struct {
+	conn    net.Conn
+	scanner *bufio.Scanner
+}
+
+// startTableServer starts a table server on a free localhost port and returns its address
+func startTableServer(t *testing.T) string {
+	t.Helper()
+	_, addr := serveTable(t)
+	return addr
+}
+
+// serveTable starts a table server on a free localhost port
+func serveTable(t *testing.T) (*TableServer, string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewTableServer(game.DefaultRules(), 7)
+	srv.Timeout = 5 * time.Second
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+	return srv, l.Addr().String()
+}
+
+func dial(t *testing.T, addr string) *testClient {
+	t.Helper()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	c := &testClient{conn: conn, scanner: bufio.NewScanner(conn)}
+	c.scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	if verb, _, err := c.next(); err != nil || verb != "HELLO" {
+		t.Fatalf("expected HELLO, got %q (%v)", verb, err)
+	}
+	return c
+}
+
+func (c *testClient) send(format string, args ...any) {
+	fmt.Fprintf(c.conn, format+"\n", args...)
+}
+
+// next reads the next line from the server, split into its keyword and argument
+func (c *testClient) next() (string, string, error) {
+	c.conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	if !c.scanner.Scan() {
+		if err := c.scanner.Err(); err != nil {
+			return "", "", err
+		}
+		return "", "", fmt.Errorf("connection closed")
+	}
+	verb, arg, _ := strings.Cut(c.scanner.Text(), " ")
+	return verb, arg, nil
+}
+
+// expect skips lines until one with the keyword arrives and returns its argument
+func (c *testClient) expect(verb string) (string, error) {
+	for {
+		v, arg, err := c.next()
+		if err != nil {
+			return "", fmt.Errorf("waiting for %s: %w", verb, err)
+		}
+		if v == verb {
+			return arg, nil
+		}
+	}
+}
+
+// play bets 10 and stands on every hand until it has seen rounds results with every
+// seat in them, then leaves the table. It returns every result it saw.
+func (c *testClient) play(name string, seats int, rounds int) ([]RoundResult, error) {
+	c.send("JOIN %s", name)
+	var results []RoundResult
+	full := 0
+	for {
+		verb, arg, err := c.next()
+		if err != nil {
+			return results, err
+		}
+		switch verb {
+		case "BET":
+			if full >= rounds {
+				c.send("LEAVE")
+			} else {
+				c.send("BET 10")
+			}
+		case "INSURANCE":
+			c.send("INSURANCE 0")
+		case "ACT":
+			c.send("ACT stand")
+		case "RESULT":
+			var result RoundResult
+			if err := json.Unmarshal([]byte(arg), &result); err != nil {
+				return results, err
+			}
+			results = append(results, result)
+			if len(result.Seats) == seats {
+				full++
+			}
+		case "LEFT":
+			return results, nil
+		case "ERROR":
+			return results, fmt.Errorf("server error: %s", arg)
+		}
+	}
+}
+
+func TestTableServerPlaysRoundsForEverySeat(t *testing.T) {
+	addr := startTableServer(t)
+	names := []string{"ann", "bob", "cat"}
+
+	var wg sync.WaitGroup
+	results := make([][]RoundResult, len(names))
+	errs := make([]error, len(names))
+	for i, name := range names {
+		c := dial(t, addr)
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			results[i], errs[i] = c.play(name, len(names), 3)
+		}(i, name)
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		if errs[i] != nil {
+			t.Fatalf("%s: %v", name, errs[i])
+		}
+
+		// Every chip won or lost is accounted for in the reported bank
+		bank := game.StartingBank
+		for _, result := range results[i] {
+			for _, seat := range result.Seats {
+				if seat.Name != name {
+					continue
+				}
+				bank += seat.Net
+				if seat.Bank != bank {
+					t.Errorf("%s: round %d reports bank %d, expected %d", name, result.Round, seat.Bank, bank)
+				}
+				for _, hand := range seat.Hands {
+					if hand.Bet != 10 {
+						t.Errorf("%s: round %d hand bet %d, expected 10", name, result.Round, hand.Bet)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestTableServerHidesHoleCardUntilDealerPlays(t *testing.T) {
+	addr := startTableServer(t)
+	watcher := dial(t, addr)
+	player := dial(t, addr)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := player.play("ann", 1, 1)
+		done <- err
+	}()
+
+	sawHidden := false
+	for {
+		verb, arg, err := watcher.next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if verb == "RESULT" {
+			break
+		}
+		if verb != "STATE" {
+			continue
+		}
+		var snap TableSnapshot
+		if err := json.Unmarshal([]byte(arg), &snap); err != nil {
+			t.Fatal(err)
+		}
+		if snap.Prompt == "act" || snap.Prompt == "insurance" {
+			sawHidden = true
+			if !snap.Dealer.HoleHidden || len(snap.Dealer.Cards) != 1 || snap.Dealer.Value != 0 {
+				t.Fatalf("hole card visible during the %s prompt: %+v", snap.Prompt, snap.Dealer)
+			}
+		}
+	}
+
+	// After the round the whole dealer hand is shown
+	arg, err := watcher.expect("STATE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snap TableSnapshot
+	if err := json.Unmarshal([]byte(arg), &snap); err != nil {
+		t.Fatal(err)
+	}
+	if snap.Dealer.HoleHidden || len(snap.Dealer.Cards) < 2 {
+		t.Errorf("dealer hand not revealed after the round: %+v", snap.Dealer)
+	}
+	if !sawHidden {
+		t.Error("no decision was prompted during the round")
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTableServerRejectsCommandsOutOfTurn(t *testing.T) {
+	addr := startTableServer(t)
+	c := dial(t, addr)
+
+	for _, line := range []string{"ACT stand", "BET 10", "SHUFFLE", "JOIN"} {
+		c.send(line)
+		if _, err := c.expect("ERROR"); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+	}
+
+	c.send("STATE")
+	arg, err := c.expect("STATE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snap TableSnapshot
+	if err := json.Unmarshal([]byte(arg), &snap); err != nil {
+		t.Fatal(err)
+	}
+	if len(snap.Seats) != 0 {
+		t.Errorf("expected an empty table, got %d seats", len(snap.Seats))
+	}
+
+	c.send("QUIT")
+	if _, err := c.expect("BYE"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTableServerCarriesOnWhenAPlayerDisconnects(t *testing.T) {
+	addr := startTableServer(t)
+	stayer := dial(t, addr)
+	quitter := dial(t, addr)
+
+	// Once ann is seated, bob joins and drops the connection at his first decision
+	quit := func() {
+		quitter.send("JOIN bob")
+		for {
+			verb, _, err := quitter.next()
+			if err != nil {
+				return
+			}
+			switch verb {
+			case "BET":
+				quitter.send("BET 10")
+			case "INSURANCE":
+				quitter.send("INSURANCE 0")
+			case "ACT":
+				quitter.conn.Close()
+				return
+			}
+		}
+	}
+
+	stayer.send("JOIN ann")
+	sawBob := false
+	for {
+		verb, arg, err := stayer.next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch verb {
+		case "WELCOME":
+			go quit()
+		case "BET":
+			stayer.send("BET 10")
+		case "INSURANCE":
+			stayer.send("INSURANCE 0")
+		case "ACT":
+			stayer.send("ACT stand")
+		case "LEFT", "ERROR":
+			t.Fatalf("ann got %s %s", verb, arg)
+		case "RESULT":
+			var result RoundResult
+			if err := json.Unmarshal([]byte(arg), &result); err != nil {
+				t.Fatal(err)
+			}
+			if len(result.Seats) == 2 {
+				sawBob = true
+			} else if sawBob {
+				// Bob's seat was given up once he went away
+				if result.Seats[0].Name != "ann" {
+					t.Fatalf("unexpected seats after disconnect: %+v", result.Seats)
+				}
+				return
+			}
+		}
+	}
+}
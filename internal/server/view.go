@@ -0,0 +1,66 @@
+package server
+
+import "github.com/DanDo385/blackjack-cli/internal/game"
+
+// HandView is a player hand as every client sees it
+type HandView struct {
+	Cards       []game.Card `json:"cards"`
+	Bet         int         `json:"bet"`
+	Value       int         `json:"value"`
+	Soft        bool        `json:"soft,omitempty"`
+	Bust        bool        `json:"bust,omitempty"`
+	Blackjack   bool        `json:"blackjack,omitempty"`
+	Doubled     bool        `json:"doubled,omitempty"`
+	Surrendered bool        `json:"surrendered,omitempty"`
+	Insurance   int         `json:"insurance,omitempty"`
+	Spot        int         `json:"spot,omitempty"`
+}
+
+// DealerView is the dealer's hand as the players see it
+// While the hole card is face down, Cards holds only the face-up cards and Value is
+// left out.
+type DealerView struct {
+	Cards      []game.Card `json:"cards"`
+	HoleHidden bool        `json:"hole_hidden,omitempty"`
+	Value      int         `json:"value,omitempty"`
+}
+
+// newHandView returns the view of a player hand
+func newHandView(hand *game.Hand) HandView {
+	return HandView{
+		Cards:       append([]game.Card{}, hand.Cards...),
+		Bet:         hand.Bet,
+		Value:       hand.Value(),
+		Soft:        hand.IsSoft(),
+		Bust:        hand.IsBust(),
+		Blackjack:   hand.IsBlackjack(),
+		Doubled:     hand.Doubled,
+		Surrendered: hand.Surrendered,
+		Insurance:   hand.InsuranceBet,
+		Spot:        hand.Spot,
+	}
+}
+
+// NewHandViews returns the views of a list of player hands
+func NewHandViews(hands []*game.Hand) []HandView {
+	views := make([]HandView, 0, len(hands))
+	for _, hand := range hands {
+		views = append(views, newHandView(hand))
+	}
+	return views
+}
+
+// NewDealerView returns the view of the dealer's hand, hiding the hole card if asked
+func NewDealerView(dealer *game.Hand, hideHole bool) DealerView {
+	if dealer == nil {
+		return DealerView{Cards: []game.Card{}}
+	}
+	if hideHole && len(dealer.Cards) >= 2 {
+		return DealerView{Cards: append([]game.Card{}, dealer.Cards[1:]...), HoleHidden: true}
+	}
+	view := DealerView{Cards: append([]game.Card{}, dealer.Cards...)}
+	if len(dealer.Cards) > 0 {
+		view.Value = dealer.Value()
+	}
+	return view
+}
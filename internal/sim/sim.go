@@ -0,0 +1,285 @@
+package sim
+
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+	"github.com/DanDo385/blackjack-cli/internal/strategy"
+)
+
+// BaseBet is the flat bet placed every round, in chips
+// It is large enough that 3:2 and 6:5 blackjack payouts come out in whole chips.
+const BaseBet = 10
+
+// bankroll is the bank each batch's game starts with, far more than a batch can lose,
+// so the simulation never runs short of chips
+const bankroll = math.MaxInt32
+
+// BatchSize is the number of rounds played from each derived seed
+// Batches, not workers, own the random streams, so the totals for a given master
+// seed are identical whatever the number of workers.
+const BatchSize = 10000
+
+// Strategy chooses an action for the hand from the available actions
+type Strategy func(hand *game.Hand, upcard game.Card, rules game.RuleSet, available []game.Action) game.Action
+
+// Strategies maps strategy names to their implementations
+var Strategies = map[string]Strategy{
+	"basic":      basicStrategy,
+	"mimic":      mimicDealer,
+	"never-bust": neverBust,
+}
+
+// StrategyByName looks up a strategy by name
+func StrategyByName(name string) (Strategy, error) {
+	strat, ok := Strategies[name]
+	if !ok {
+		names := make([]string, 0, len(Strategies))
+		for n := range Strategies {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown strategy %q (available: %s)", name, strings.Join(names, ", "))
+	}
+	return strat, nil
+}
+
+// basicStrategy plays the basic-strategy chart for the table rules
+func basicStrategy(hand *game.Hand, upcard game.Card, rules game.RuleSet, available []game.Action) game.Action {
+	return strategy.Recommend(hand, upcard, rules, available).Action
+}
+
+// mimicDealer plays the hand like the dealer: hit to 17, never double, split or surrender
+func mimicDealer(hand *game.Hand, upcard game.Card, rules game.RuleSet, available []game.Action) game.Action {
+	if game.DealerShouldHit(hand, rules) && game.ContainsAction(available, game.ActionHit) {
+		return game.ActionHit
+	}
+	return game.ActionStand
+}
+
+// neverBust stands on any hard total that could bust and hits soft hands below 18
+func neverBust(hand *game.Hand, upcard game.Card, rules game.RuleSet, available []game.Action) game.Action {
+	if !game.ContainsAction(available, game.ActionHit) {
+		return game.ActionStand
+	}
+	if hand.IsSoft() {
+		if hand.Value() < 18 {
+			return game.ActionHit
+		}
+		return game.ActionStand
+	}
+	if hand.Value() < 12 {
+		return game.ActionHit
+	}
+	return game.ActionStand
+}
+
+// Config describes a simulation run
+type Config struct {
+	Rules    game.RuleSet
+	Strategy Strategy
+	Rounds   int
+	Seed     int64 // Master seed; each batch's seed is derived from it
+	Workers  int   // Number of goroutines; 0 uses every CPU
+}
+
+// Result accumulates the outcome of simulated rounds
+// All totals are integers so results can be merged exactly.
+type Result struct {
+	Rounds   int64
+	Hands    int64
+	Wagered  int64 // Total chips bet, including doubles and splits
+	Net      int64 // Total chips won (negative when lost)
+	NetSq    int64 // Sum of the squared net result of each round
+	Outcomes [5]int64
+}
+
+// Run plays cfg.Rounds rounds of flat BaseBet bets with no I/O and returns the totals
+// The rounds are split into batches of BatchSize that are shared out between
+// cfg.Workers goroutines. Each batch is played on its own Game, and so its own
+// *rand.Rand, seeded by DeriveSeed(cfg.Seed, batch); the batch totals are
+// merged in batch order once every worker has finished.
+func Run(cfg Config) Result {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	batches := (cfg.Rounds + BatchSize - 1) / BatchSize
+	results := make([]Result, batches)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for batch := range jobs {
+				rounds := BatchSize
+				if remaining := cfg.Rounds - batch*BatchSize; remaining < rounds {
+					rounds = remaining
+				}
+				// Each batch writes only to its own slot, so no locking is needed
+				results[batch] = runBatch(cfg, batch, rounds)
+			}
+		}()
+	}
+
+	for batch := 0; batch < batches; batch++ {
+		jobs <- batch
+	}
+	close(jobs)
+	wg.Wait()
+
+	var total Result
+	for _, result := range results {
+		total.Merge(result)
+	}
+	return total
+}
+
+// runBatch plays one batch of rounds on a fresh game and shoe
+func runBatch(cfg Config, batch int, rounds int) Result {
+	g := game.NewGameWithBank(cfg.Rules, game.DeriveSeed(cfg.Seed, uint64(batch)), bankroll)
+
+	var result Result
+	for i := 0; i < rounds; i++ {
+		result.add(playRound(g, cfg.Strategy))
+	}
+	return result
+}
+
+// roundResult is the outcome of a single round
+type roundResult struct {
+	net      int
+	wagered  int
+	outcomes []game.Outcome
+}
+
+func (r *Result) add(round roundResult) {
+	r.Rounds++
+	r.Hands += int64(len(round.outcomes))
+	r.Wagered += int64(round.wagered)
+	r.Net += int64(round.net)
+	r.NetSq += int64(round.net) * int64(round.net)
+	for _, outcome := range round.outcomes {
+		r.Outcomes[outcome]++
+	}
+}
+
+// Merge adds another result's totals to this one
+func (r *Result) Merge(other Result) {
+	r.Rounds += other.Rounds
+	r.Hands += other.Hands
+	r.Wagered += other.Wagered
+	r.Net += other.Net
+	r.NetSq += other.NetSq
+	for i := range r.Outcomes {
+		r.Outcomes[i] += other.Outcomes[i]
+	}
+}
+
+// flatBettor is the simulated player: a flat BaseBet, no insurance and the strategy's plays
+type flatBettor struct {
+	strat Strategy
+}
+
+func (p flatBettor) Bet(g *game.Game) (int, error) {
+	return BaseBet, nil
+}
+
+func (p flatBettor) Insurance(g *game.Game, max int) (int, error) {
+	return 0, nil
+}
+
+func (p flatBettor) Act(g *game.Game, hand *game.Hand, available []game.Action) (game.Action, error) {
+	return p.strat(hand, g.DealerUpcard(), g.Rules, available), nil
+}
+
+// playRound plays one round at BaseBet, declining insurance, and reports the net result
+func playRound(g *game.Game, strat Strategy) roundResult {
+	before := g.Bank
+
+	if err := g.PlayRound(flatBettor{strat}); err != nil {
+		panic(fmt.Sprintf("sim: %v", err))
+	}
+
+	if err := g.CheckBank(); err != nil {
+		panic(fmt.Sprintf("sim: %v", err))
+	}
+
+	round := roundResult{net: g.Bank - before}
+	for _, hand := range g.PlayerHands {
+		round.wagered += hand.Bet
+		round.outcomes = append(round.outcomes, game.DetermineOutcome(hand, g.DealerHand))
+	}
+	return round
+}
+
+// HouseEdge returns the house edge as a fraction of the initial bet
+func (r Result) HouseEdge() float64 {
+	if r.Rounds == 0 {
+		return 0
+	}
+	return -float64(r.Net) / float64(r.Rounds) / BaseBet
+}
+
+// StdDev returns the standard deviation of a round's result, in initial bets
+func (r Result) StdDev() float64 {
+	if r.Rounds < 2 {
+		return 0
+	}
+	n := float64(r.Rounds)
+	mean := float64(r.Net) / n
+	variance := (float64(r.NetSq) - n*mean*mean) / (n - 1)
+	if variance < 0 {
+		variance = 0
+	}
+	return math.Sqrt(variance) / BaseBet
+}
+
+// ConfidenceInterval returns the interval around the house edge at the given z-score
+// (1.96 for 95%, 2.576 for 99%)
+func (r Result) ConfidenceInterval(z float64) (low float64, high float64) {
+	if r.Rounds == 0 {
+		return 0, 0
+	}
+	margin := z * r.StdDev() / math.Sqrt(float64(r.Rounds))
+	edge := r.HouseEdge()
+	return edge - margin, edge + margin
+}
+
+// Report renders the result as a plain-text summary
+func (r Result) Report() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("Rounds played:     %d\n", r.Rounds))
+	sb.WriteString(fmt.Sprintf("Hands played:      %d\n", r.Hands))
+	sb.WriteString(fmt.Sprintf("Total wagered:     %d chips (%.4f initial bets per round)\n",
+		r.Wagered, float64(r.Wagered)/math.Max(float64(r.Rounds), 1)/BaseBet))
+	sb.WriteString(fmt.Sprintf("Net result:        %+d chips\n", r.Net))
+	sb.WriteString("\n")
+
+	sb.WriteString(fmt.Sprintf("House edge:        %+.4f%% of initial bet\n", r.HouseEdge()*100))
+	sb.WriteString(fmt.Sprintf("Player EV:         %+.4f%% per round\n", -r.HouseEdge()*100))
+	sb.WriteString(fmt.Sprintf("Std dev per round: %.4f initial bets\n", r.StdDev()))
+	low, high := r.ConfidenceInterval(1.96)
+	sb.WriteString(fmt.Sprintf("95%% CI:            [%+.4f%%, %+.4f%%]\n", low*100, high*100))
+	low, high = r.ConfidenceInterval(2.576)
+	sb.WriteString(fmt.Sprintf("99%% CI:            [%+.4f%%, %+.4f%%]\n", low*100, high*100))
+	sb.WriteString("\n")
+
+	sb.WriteString("Outcome frequencies (per hand):\n")
+	for _, outcome := range []game.Outcome{game.OutcomeWin, game.OutcomeLose, game.OutcomePush, game.OutcomeBlackjack, game.OutcomeSurrender} {
+		count := r.Outcomes[outcome]
+		sb.WriteString(fmt.Sprintf("  %-10s %12d  %7.3f%%\n", outcome.String()+":", count,
+			float64(count)*100/math.Max(float64(r.Hands), 1)))
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,61 @@
+package sim
+
+import (
+	"testing"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// TestHouseEdge checks that seeded runs repeat exactly and land near the published edges
+// Basic strategy under the classic rules is within half a percent of even; mimicking the
+// dealer gives the house about 5.5%.
+func TestHouseEdge(t *testing.T) {
+	tests := []struct {
+		strategy string
+		low      float64
+		high     float64
+	}{
+		{"basic", -0.015, 0.015},
+		{"mimic", 0.03, 0.08},
+	}
+	for _, tt := range tests {
+		t.Run(tt.strategy, func(t *testing.T) {
+			cfg := Config{Rules: game.DefaultRules(), Strategy: Strategies[tt.strategy], Rounds: 100000, Seed: 42}
+			result := Run(cfg)
+			if again := Run(cfg); again != result {
+				t.Fatalf("the same seed gave different results:\n%s\n%s", result.Report(), again.Report())
+			}
+			if edge := result.HouseEdge(); edge < tt.low || edge > tt.high {
+				t.Errorf("house edge is %+.4f%%, expected between %+.1f%% and %+.1f%%\n%s",
+					edge*100, tt.low*100, tt.high*100, result.Report())
+			}
+		})
+	}
+}
+
+func TestRunIsIdenticalForAnyWorkers(t *testing.T) {
+	for _, rules := range []game.RuleSet{game.DefaultRules(), game.DowntownRules()} {
+		cfg := Config{
+			Rules:    rules,
+			Strategy: Strategies["basic"],
+			// Not a whole number of batches, so the short last batch is covered too
+			Rounds: 5*BatchSize + 1234,
+			Seed:   42,
+		}
+
+		cfg.Workers = 1
+		one := Run(cfg)
+		cfg.Workers = 8
+		eight := Run(cfg)
+
+		if one != eight {
+			t.Errorf("%s: 1 worker gave %+v, 8 workers gave %+v", rules.Name, one, eight)
+		}
+		if one.Report() != eight.Report() {
+			t.Errorf("%s: reports differ:\n%s\n%s", rules.Name, one.Report(), eight.Report())
+		}
+		if one.Rounds != int64(cfg.Rounds) {
+			t.Errorf("%s: played %d rounds, expected %d", rules.Name, one.Rounds, cfg.Rounds)
+		}
+	}
+}
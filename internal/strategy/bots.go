@@ -0,0 +1,145 @@
+package strategy
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// BasicBot flat-bets, never takes insurance and plays basic strategy
+type BasicBot struct {
+	BetSize int
+}
+
+// NewBasicBot creates a basic-strategy bot betting betSize every round
+func NewBasicBot(betSize int) *BasicBot {
+	return &BasicBot{BetSize: betSize}
+}
+
+func (b *BasicBot) Bet(g *game.Game) (int, error) {
+	return tableBet(g, b.BetSize)
+}
+
+func (b *BasicBot) Insurance(g *game.Game, max int) (int, error) {
+	return 0, nil
+}
+
+func (b *BasicBot) Act(g *game.Game, hand *game.Hand, available []game.Action) (game.Action, error) {
+	return Recommend(hand, g.DealerUpcard(), g.Rules, available).Action, nil
+}
+
+// RandomBot flat-bets and makes every decision at random
+// It is useful for exercising unusual paths through the engine.
+type RandomBot struct {
+	BetSize int
+	rng     *rand.Rand
+}
+
+// NewRandomBot creates a random bot betting betSize every round
+func NewRandomBot(betSize int, rng *rand.Rand) *RandomBot {
+	return &RandomBot{BetSize: betSize, rng: rng}
+}
+
+func (b *RandomBot) Bet(g *game.Game) (int, error) {
+	return tableBet(g, b.BetSize)
+}
+
+func (b *RandomBot) Insurance(g *game.Game, max int) (int, error) {
+	if b.rng.Intn(2) == 0 {
+		return 0, nil
+	}
+	return max, nil
+}
+
+func (b *RandomBot) Act(g *game.Game, hand *game.Hand, available []game.Action) (game.Action, error) {
+	return available[b.rng.Intn(len(available))], nil
+}
+
+// CountingBot keeps its own count, spreads its bet with the true count, takes insurance
+// at a true count of +3 or more and otherwise plays basic strategy
+type CountingBot struct {
+	Unit    int // Bet at a true count of +1 or less
+	Spread  int // Largest bet as a multiple of Unit
+	Counter *game.Counter
+}
+
+// NewCountingBot creates a counting bot that follows the game's cards with the given system
+func NewCountingBot(g *game.Game, system game.CountSystem, unit int, spread int) *CountingBot {
+	b := &CountingBot{Unit: unit, Spread: spread, Counter: game.NewCounter(system, g.Rules.NumDecks)}
+	g.Subscribe(b.Counter.Handle)
+	return b
+}
+
+// trueCount returns the true count, or the running count for an unbalanced system
+func (b *CountingBot) trueCount(g *game.Game) float64 {
+	if !b.Counter.System.Balanced() {
+		return float64(b.Counter.Running)
+	}
+	return b.Counter.TrueCount(g.Shoe.DecksRemaining())
+}
+
+func (b *CountingBot) Bet(g *game.Game) (int, error) {
+	units := int(math.Floor(b.trueCount(g)))
+	if units < 1 {
+		units = 1
+	}
+	if units > b.Spread {
+		units = b.Spread
+	}
+	return tableBet(g, b.Unit*units)
+}
+
+func (b *CountingBot) Insurance(g *game.Game, max int) (int, error) {
+	if b.trueCount(g) >= 3 {
+		return max, nil
+	}
+	return 0, nil
+}
+
+func (b *CountingBot) Act(g *game.Game, hand *game.Hand, available []game.Action) (game.Action, error) {
+	return Recommend(hand, g.DealerUpcard(), g.Rules, available).Action, nil
+}
+
+// tableBet fits a bet within the table limits and the bank, or quits if it can't
+func tableBet(g *game.Game, bet int) (int, error) {
+	if bet < g.Rules.MinBet {
+		bet = g.Rules.MinBet
+	}
+	if max := g.MaxBet(); bet > max {
+		bet = max
+	}
+	if bet < g.Rules.MinBet {
+		return 0, game.ErrQuit
+	}
+	return bet, nil
+}
+
+// Bots lists the names accepted by NewBot
+var Bots = []string{"basic", "random", "count"}
+
+// randomBotStream numbers the random streams given to random bots, one per seat
+// Deriving them from the session seed keeps bot sessions reproducible without tying
+// the bot's choices to the shuffle, which is drawn from the seed itself.
+const randomBotStream = 1 << 32
+
+// NewBot creates a bot by name for the game, betting unit chips a round
+// The counting bot counts Hi-Lo and spreads its bet from 1 to 8 units.
+func NewBot(name string, g *game.Game, unit int) (game.Player, error) {
+	switch name {
+	case "basic":
+		return NewBasicBot(unit), nil
+	case "random":
+		seed := game.DeriveSeed(g.Seed, randomBotStream+uint64(g.SeatIndex()))
+		return NewRandomBot(unit, game.NewSeededRand(seed)), nil
+	case "count":
+		return NewCountingBot(g, game.CountHiLo, unit, 8), nil
+	default:
+		names := append([]string(nil), Bots...)
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown bot %q (available: %s)", name, strings.Join(names, ", "))
+	}
+}
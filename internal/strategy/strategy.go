@@ -0,0 +1,289 @@
+package strategy
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// Chart codes used in the strategy tables
+//
+//	H  - hit
+//	S  - stand
+//	Dh - double if allowed, otherwise hit
+//	Ds - double if allowed, otherwise stand
+//	P  - split
+//	Ph - split if double after split is allowed, otherwise hit
+//	Rh - surrender if allowed, otherwise hit
+//	Rs - surrender if allowed, otherwise stand
+//	Rp - surrender if allowed, otherwise split
+type code string
+
+const (
+	hit            code = "H"
+	stand          code = "S"
+	doubleHit      code = "Dh"
+	doubleStand    code = "Ds"
+	split          code = "P"
+	splitDAS       code = "Ph"
+	surrenderHit   code = "Rh"
+	surrenderStand code = "Rs"
+	surrenderSplit code = "Rp"
+)
+
+// Each row lists the play against dealer upcards 2, 3, 4, 5, 6, 7, 8, 9, 10, A
+// The tables are the standard 4-8 deck, dealer stands on soft 17 charts;
+// rule-dependent cells are adjusted in lookup functions below.
+
+var hardTable = map[int][10]code{
+	8:  {hit, hit, hit, hit, hit, hit, hit, hit, hit, hit},
+	9:  {hit, doubleHit, doubleHit, doubleHit, doubleHit, hit, hit, hit, hit, hit},
+	10: {doubleHit, doubleHit, doubleHit, doubleHit, doubleHit, doubleHit, doubleHit, doubleHit, hit, hit},
+	11: {doubleHit, doubleHit, doubleHit, doubleHit, doubleHit, doubleHit, doubleHit, doubleHit, doubleHit, hit},
+	12: {hit, hit, stand, stand, stand, hit, hit, hit, hit, hit},
+	13: {stand, stand, stand, stand, stand, hit, hit, hit, hit, hit},
+	14: {stand, stand, stand, stand, stand, hit, hit, hit, hit, hit},
+	15: {stand, stand, stand, stand, stand, hit, hit, hit, surrenderHit, hit},
+	16: {stand, stand, stand, stand, stand, hit, hit, surrenderHit, surrenderHit, surrenderHit},
+	17: {stand, stand, stand, stand, stand, stand, stand, stand, stand, stand},
+}
+
+var softTable = map[int][10]code{
+	13: {hit, hit, hit, doubleHit, doubleHit, hit, hit, hit, hit, hit},
+	14: {hit, hit, hit, doubleHit, doubleHit, hit, hit, hit, hit, hit},
+	15: {hit, hit, doubleHit, doubleHit, doubleHit, hit, hit, hit, hit, hit},
+	16: {hit, hit, doubleHit, doubleHit, doubleHit, hit, hit, hit, hit, hit},
+	17: {hit, doubleHit, doubleHit, doubleHit, doubleHit, hit, hit, hit, hit, hit},
+	18: {stand, doubleStand, doubleStand, doubleStand, doubleStand, stand, stand, hit, hit, hit},
+	19: {stand, stand, stand, stand, stand, stand, stand, stand, stand, stand},
+}
+
+// pairTable is keyed by the blackjack value of the paired card (11 for Aces)
+// Fives have no entry, and hit or stand cells mean the pair is played as its total.
+var pairTable = map[int][10]code{
+	2:  {splitDAS, splitDAS, split, split, split, split, hit, hit, hit, hit},
+	3:  {splitDAS, splitDAS, split, split, split, split, hit, hit, hit, hit},
+	4:  {hit, hit, hit, splitDAS, splitDAS, hit, hit, hit, hit, hit},
+	6:  {splitDAS, split, split, split, split, hit, hit, hit, hit, hit},
+	7:  {split, split, split, split, split, split, hit, hit, hit, hit},
+	8:  {split, split, split, split, split, split, split, split, split, split},
+	9:  {split, split, split, split, split, stand, split, split, stand, stand},
+	10: {stand, stand, stand, stand, stand, stand, stand, stand, stand, stand},
+	11: {split, split, split, split, split, split, split, split, split, split},
+}
+
+// Advice is a basic-strategy recommendation for a single decision
+type Advice struct {
+	Action game.Action
+	Reason string // e.g. "soft 18 vs 9: hit"
+}
+
+func (a Advice) String() string {
+	return a.Reason
+}
+
+// Recommend returns the basic-strategy action for the hand against the dealer upcard
+// under the given rules, restricted to the actions currently available. When the
+// chart's first choice (such as double or surrender) is not available the
+// chart's fallback is used instead.
+func Recommend(hand *game.Hand, upcard game.Card, rules game.RuleSet, available []game.Action) Advice {
+	c, situation := chartCode(hand, upcard, rules, available)
+	action, preferred := resolve(c, rules, available)
+
+	reason := fmt.Sprintf("%s vs %s: %s", situation, UpcardLabel(upcard), actionName(action))
+	if preferred != action {
+		reason = fmt.Sprintf("%s vs %s: %s (%s not allowed)", situation, UpcardLabel(upcard), actionName(action), actionName(preferred))
+	}
+	return Advice{Action: action, Reason: reason}
+}
+
+// UpcardLabel returns the chart label for a dealer upcard: 2-10 or A
+func UpcardLabel(upcard game.Card) string {
+	if upcard.IsAce() {
+		return "A"
+	}
+	return fmt.Sprintf("%d", upcard.Rank.Value())
+}
+
+// Situation describes the hand the way the strategy chart does, e.g. "hard 16", "soft 18" or "pair of 8s"
+// Pairs are only described as such when they can be split and appear in the pair chart.
+func Situation(hand *game.Hand, canSplit bool) string {
+	if canSplit && isPair(hand) {
+		if _, ok := pairTable[pairValue(hand.Cards[0])]; ok {
+			return "pair of " + pairLabel(hand.Cards[0])
+		}
+	}
+	if hand.IsSoft() {
+		return fmt.Sprintf("soft %d", hand.Value())
+	}
+	return fmt.Sprintf("hard %d", hand.Value())
+}
+
+// chartCode looks up the chart cell for the hand and describes the situation
+func chartCode(hand *game.Hand, upcard game.Card, rules game.RuleSet, available []game.Action) (code, string) {
+	col := upcardIndex(upcard)
+	canSplit := game.ContainsAction(available, game.ActionSplit)
+	situation := Situation(hand, canSplit)
+
+	if canSplit && isPair(hand) {
+		if row, ok := pairTable[pairValue(hand.Cards[0])]; ok {
+			c := adjustPair(row[col], pairValue(hand.Cards[0]), col, rules)
+			if c != "" {
+				return c, situation
+			}
+		}
+		// Not a pair worth splitting - fall through to the total tables
+	}
+
+	if hand.IsSoft() {
+		return adjustSoft(softCode(hand.Value(), col), hand.Value(), col, rules), situation
+	}
+	return adjustHard(hardCode(hand.Value(), col), hand.Value(), col, rules), situation
+}
+
+func hardCode(total int, col int) code {
+	switch {
+	case total < 8:
+		return hit
+	case total > 17:
+		return stand
+	default:
+		return hardTable[total][col]
+	}
+}
+
+func softCode(total int, col int) code {
+	switch {
+	case total < 13:
+		return hit
+	case total > 19:
+		return stand
+	default:
+		return softTable[total][col]
+	}
+}
+
+// adjustHard applies the H17 and no-hole-card changes to the hard chart
+func adjustHard(c code, total int, col int, rules game.RuleSet) code {
+	if rules.DealerHitsSoft17 && col == aceColumn {
+		switch total {
+		case 11:
+			c = doubleHit
+		case 15:
+			c = surrenderHit
+		case 17:
+			c = surrenderStand
+		}
+	}
+	if !rules.DealerPeeks && col >= tenColumn && (c == doubleHit || c == doubleStand) {
+		// Without a peek a double against 10 or A loses twice to a dealer blackjack
+		c = hit
+	}
+	return c
+}
+
+// adjustSoft applies the H17 changes to the soft chart
+func adjustSoft(c code, total int, col int, rules game.RuleSet) code {
+	if rules.DealerHitsSoft17 {
+		switch {
+		case total == 18 && col == 0:
+			c = doubleStand
+		case total == 19 && col == 4:
+			c = doubleStand
+		}
+	}
+	return c
+}
+
+// adjustPair applies the H17 and no-hole-card changes to the pair chart
+// Returns an empty code if the pair should be played as a total instead.
+func adjustPair(c code, value int, col int, rules game.RuleSet) code {
+	if rules.DealerHitsSoft17 && value == 8 && col == aceColumn {
+		c = surrenderSplit
+	}
+	if !rules.DealerPeeks {
+		if value == 8 && col >= tenColumn {
+			return ""
+		}
+		if value == 11 && col == aceColumn {
+			return ""
+		}
+	}
+	if c == hit || c == stand {
+		return ""
+	}
+	return c
+}
+
+// resolve turns a chart code into an available action
+// Returns the action to take and the chart's first choice.
+func resolve(c code, rules game.RuleSet, available []game.Action) (game.Action, game.Action) {
+	var preferred, fallback game.Action
+
+	switch c {
+	case hit:
+		preferred, fallback = game.ActionHit, game.ActionHit
+	case stand:
+		preferred, fallback = game.ActionStand, game.ActionStand
+	case doubleHit:
+		preferred, fallback = game.ActionDouble, game.ActionHit
+	case doubleStand:
+		preferred, fallback = game.ActionDouble, game.ActionStand
+	case split:
+		preferred, fallback = game.ActionSplit, game.ActionHit
+	case splitDAS:
+		if rules.DoubleAfterSplit {
+			preferred, fallback = game.ActionSplit, game.ActionHit
+		} else {
+			preferred, fallback = game.ActionHit, game.ActionHit
+		}
+	case surrenderHit:
+		preferred, fallback = game.ActionSurrender, game.ActionHit
+	case surrenderStand:
+		preferred, fallback = game.ActionSurrender, game.ActionStand
+	case surrenderSplit:
+		preferred, fallback = game.ActionSurrender, game.ActionSplit
+	}
+
+	if game.ContainsAction(available, preferred) {
+		return preferred, preferred
+	}
+	if game.ContainsAction(available, fallback) {
+		return fallback, preferred
+	}
+	// Split aces and the like may only be able to stand
+	return game.ActionStand, preferred
+}
+
+const (
+	tenColumn = 8
+	aceColumn = 9
+)
+
+// upcardIndex maps a dealer upcard to its chart column (2 -> 0, ..., 10 -> 8, A -> 9)
+func upcardIndex(upcard game.Card) int {
+	if upcard.IsAce() {
+		return aceColumn
+	}
+	return upcard.Rank.Value() - 2
+}
+
+func isPair(hand *game.Hand) bool {
+	return len(hand.Cards) == 2 && hand.Cards[0].Rank == hand.Cards[1].Rank
+}
+
+func pairValue(card game.Card) int {
+	return card.Rank.Value()
+}
+
+func pairLabel(card game.Card) string {
+	if card.IsAce() {
+		return "aces"
+	}
+	return card.Rank.String() + "s"
+}
+
+func actionName(action game.Action) string {
+	return strings.ToLower(action.String())
+}
@@ -0,0 +1,131 @@
+package strategy
+
+import (
+	"testing"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// deal builds a hand from cards in ParseCards notation
+func deal(t *testing.T, cards string) *game.Hand {
+	t.Helper()
+	parsed, err := game.ParseCards(cards)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hand := game.NewHand(10)
+	for _, card := range parsed {
+		hand.Add(card)
+	}
+	return hand
+}
+
+// offered returns the actions the game offers on the hand's first decision under the rules
+func offered(hand *game.Hand, rules game.RuleSet) []game.Action {
+	available := []game.Action{game.ActionHit, game.ActionStand}
+	if hand.CanDouble(rules) {
+		available = append(available, game.ActionDouble)
+	}
+	if hand.CanSplit(rules) {
+		available = append(available, game.ActionSplit)
+	}
+	if hand.CanSurrender(rules) {
+		available = append(available, game.ActionSurrender)
+	}
+	return available
+}
+
+// TestRecommend checks known cells of the charts and the hint given for them
+func TestRecommend(t *testing.T) {
+	classic := game.DefaultRules()
+	h17 := classic
+	h17.DealerHitsSoft17 = true
+	noDAS := classic
+	noDAS.DoubleAfterSplit = false
+	downtown := game.DowntownRules()
+	european := game.EuropeanRules()
+
+	tests := []struct {
+		name   string
+		rules  game.RuleSet
+		hand   string
+		upcard string
+		want   game.Action
+		hint   string
+	}{
+		// Hard chart
+		{"hard 9 vs 2", classic, "5S 4C", "2H", game.ActionHit, "hard 9 vs 2: hit"},
+		{"hard 9 vs 3", classic, "5S 4C", "3H", game.ActionDouble, "hard 9 vs 3: double"},
+		{"hard 10 vs 9", classic, "6S 4C", "9H", game.ActionDouble, "hard 10 vs 9: double"},
+		{"hard 12 vs 2", classic, "10S 2C", "2H", game.ActionHit, "hard 12 vs 2: hit"},
+		{"hard 12 vs 4", classic, "10S 2C", "4H", game.ActionStand, "hard 12 vs 4: stand"},
+		{"hard 13 vs 2", classic, "10S 3C", "2H", game.ActionStand, "hard 13 vs 2: stand"},
+		{"hard 16 vs 7", classic, "10S 6C", "7H", game.ActionHit, "hard 16 vs 7: hit"},
+		{"hard 17 vs A", classic, "10S 7C", "AH", game.ActionStand, "hard 17 vs A: stand"},
+		{"hard 11 after a hit", classic, "5S 2C 4D", "6H", game.ActionHit, "hard 11 vs 6: hit (double not allowed)"},
+
+		// Surrender
+		{"hard 16 vs 10", classic, "10S 6C", "10H", game.ActionSurrender, "hard 16 vs 10: surrender"},
+		{"hard 15 vs 10", classic, "10S 5C", "KH", game.ActionSurrender, "hard 15 vs 10: surrender"},
+		{"hard 16 vs 10 without surrender", downtown, "10S 6C", "10H", game.ActionHit, "hard 16 vs 10: hit (surrender not allowed)"},
+		{"hard 15 vs A S17", classic, "10S 5C", "AH", game.ActionHit, "hard 15 vs A: hit"},
+
+		// H17 adjustments
+		{"hard 11 vs A S17", classic, "6S 5C", "AH", game.ActionHit, "hard 11 vs A: hit"},
+		{"hard 11 vs A H17", h17, "6S 5C", "AH", game.ActionDouble, "hard 11 vs A: double"},
+		{"hard 15 vs A H17", h17, "10S 5C", "AH", game.ActionSurrender, "hard 15 vs A: surrender"},
+		{"hard 17 vs A H17", h17, "10S 7C", "AH", game.ActionSurrender, "hard 17 vs A: surrender"},
+		{"hard 17 vs A H17 without surrender", downtown, "10S 7C", "AH", game.ActionStand, "hard 17 vs A: stand (surrender not allowed)"},
+		{"soft 18 vs 2 H17", h17, "AS 7C", "2H", game.ActionDouble, "soft 18 vs 2: double"},
+		{"soft 19 vs 6 H17", h17, "AS 8C", "6H", game.ActionDouble, "soft 19 vs 6: double"},
+		{"pair of 8s vs A H17", h17, "8S 8C", "AH", game.ActionSurrender, "pair of 8s vs A: surrender"},
+		{"pair of 8s vs A H17 without surrender", downtown, "8S 8C", "AH", game.ActionSplit, "pair of 8s vs A: split (surrender not allowed)"},
+
+		// Soft chart
+		{"soft 13 vs 5", classic, "AS 2C", "5H", game.ActionDouble, "soft 13 vs 5: double"},
+		{"soft 13 vs 4", classic, "AS 2C", "4H", game.ActionHit, "soft 13 vs 4: hit"},
+		{"soft 17 vs 2", classic, "AS 6C", "2H", game.ActionHit, "soft 17 vs 2: hit"},
+		{"soft 18 vs 2", classic, "AS 7C", "2H", game.ActionStand, "soft 18 vs 2: stand"},
+		{"soft 18 vs 3", classic, "AS 7C", "3H", game.ActionDouble, "soft 18 vs 3: double"},
+		{"soft 18 vs 9", classic, "AS 7C", "9H", game.ActionHit, "soft 18 vs 9: hit"},
+		{"soft 18 after a hit", classic, "AS 3C 4D", "3H", game.ActionStand, "soft 18 vs 3: stand (double not allowed)"},
+		{"soft 19 vs 6", classic, "AS 8C", "6H", game.ActionStand, "soft 19 vs 6: stand"},
+
+		// Pair chart
+		{"pair of aces vs A", classic, "AS AC", "AH", game.ActionSplit, "pair of aces vs A: split"},
+		{"pair of 8s vs 10", classic, "8S 8C", "10H", game.ActionSplit, "pair of 8s vs 10: split"},
+		{"pair of 9s vs 7", classic, "9S 9C", "7H", game.ActionStand, "pair of 9s vs 7: stand"},
+		{"pair of 9s vs 8", classic, "9S 9C", "8H", game.ActionSplit, "pair of 9s vs 8: split"},
+		{"pair of 10s vs 6", classic, "10S 10C", "6H", game.ActionStand, "pair of 10s vs 6: stand"},
+		{"pair of 4s vs 5", classic, "4S 4C", "5H", game.ActionSplit, "pair of 4s vs 5: split"},
+		{"pair of 4s vs 5 without DAS", noDAS, "4S 4C", "5H", game.ActionHit, "pair of 4s vs 5: hit"},
+		{"pair of 2s vs 2 without DAS", noDAS, "2S 2C", "2H", game.ActionHit, "pair of 2s vs 2: hit"},
+		{"pair of 5s vs 6", classic, "5S 5C", "6H", game.ActionDouble, "hard 10 vs 6: double"},
+		{"pair of 7s vs 8", classic, "7S 7C", "8H", game.ActionHit, "pair of 7s vs 8: hit"},
+
+		// No hole card (ENHC): don't risk more against a 10 or an ace
+		{"hard 11 vs 10 ENHC", european, "6S 5C", "10H", game.ActionHit, "hard 11 vs 10: hit"},
+		{"hard 11 vs 10 with a peek", classic, "6S 5C", "10H", game.ActionDouble, "hard 11 vs 10: double"},
+		{"hard 10 vs A ENHC", european, "6S 4C", "AH", game.ActionHit, "hard 10 vs A: hit"},
+		{"hard 11 vs 9 ENHC", european, "6S 5C", "9H", game.ActionDouble, "hard 11 vs 9: double"},
+		{"pair of 8s vs 10 ENHC", european, "8S 8C", "10H", game.ActionHit, "pair of 8s vs 10: hit (surrender not allowed)"},
+		{"pair of aces vs A ENHC", european, "AS AC", "AH", game.ActionHit, "pair of aces vs A: hit"},
+		{"pair of aces vs 10 ENHC", european, "AS AC", "10H", game.ActionSplit, "pair of aces vs 10: split"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hand := deal(t, tt.hand)
+			upcard, err := game.ParseCard(tt.upcard)
+			if err != nil {
+				t.Fatal(err)
+			}
+			advice := Recommend(hand, upcard, tt.rules, offered(hand, tt.rules))
+			if advice.Action != tt.want {
+				t.Errorf("recommended %s, expected %s", advice.Action, tt.want)
+			}
+			if advice.String() != tt.hint {
+				t.Errorf("hint is %q, expected %q", advice.String(), tt.hint)
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package strategy
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// Category groups decisions for the trainer's accuracy report
+type Category int
+
+const (
+	CategoryHard Category = iota
+	CategorySoft
+	CategoryPair
+	CategorySurrender
+	CategoryInsurance
+)
+
+// Categories lists every category in report order
+var Categories = []Category{CategoryHard, CategorySoft, CategoryPair, CategorySurrender, CategoryInsurance}
+
+func (c Category) String() string {
+	switch c {
+	case CategoryHard:
+		return "Hard totals"
+	case CategorySoft:
+		return "Soft totals"
+	case CategoryPair:
+		return "Pairs"
+	case CategorySurrender:
+		return "Surrender"
+	case CategoryInsurance:
+		return "Insurance"
+	default:
+		return "Unknown"
+	}
+}
+
+// Grade is the trainer's verdict on a single decision
+type Grade struct {
+	Category Category
+	Cell     string // Chart cell, e.g. "hard 16 vs 10"
+	Chosen   string // What the player did, e.g. "hit"
+	Correct  string // What basic strategy says, e.g. "surrender"
+	Reason   string // Basic strategy explanation
+	OK       bool
+}
+
+func (g Grade) String() string {
+	if g.OK {
+		return fmt.Sprintf("✅ Correct: %s", g.Reason)
+	}
+	return fmt.Sprintf("❌ Mistake: you chose to %s. Basic strategy: %s", g.Chosen, g.Reason)
+}
+
+// tally counts graded decisions
+type tally struct {
+	total   int
+	correct int
+}
+
+// cellTally counts decisions made in a single chart cell
+type cellTally struct {
+	cell    string
+	correct string
+	total   int
+	missed  int
+}
+
+// Trainer grades decisions against basic strategy and keeps per-category accuracy
+type Trainer struct {
+	categories map[Category]*tally
+	cells      map[string]*cellTally
+}
+
+// NewTrainer creates a trainer with an empty session record
+func NewTrainer() *Trainer {
+	return &Trainer{
+		categories: make(map[Category]*tally),
+		cells:      make(map[string]*cellTally),
+	}
+}
+
+// GradeAction grades the action the player chose for the hand, before it is performed
+func (t *Trainer) GradeAction(hand *game.Hand, upcard game.Card, rules game.RuleSet, available []game.Action, chosen game.Action) Grade {
+	advice := Recommend(hand, upcard, rules, available)
+	situation := Situation(hand, game.ContainsAction(available, game.ActionSplit))
+
+	category := CategoryHard
+	switch {
+	case advice.Action == game.ActionSurrender || chosen == game.ActionSurrender:
+		category = CategorySurrender
+	case strings.HasPrefix(situation, "pair"):
+		category = CategoryPair
+	case hand.IsSoft():
+		category = CategorySoft
+	}
+
+	grade := Grade{
+		Category: category,
+		Cell:     fmt.Sprintf("%s vs %s", situation, UpcardLabel(upcard)),
+		Chosen:   actionName(chosen),
+		Correct:  actionName(advice.Action),
+		Reason:   advice.Reason,
+		OK:       chosen == advice.Action,
+	}
+	t.record(grade)
+	return grade
+}
+
+// GradeInsurance grades an insurance decision; basic strategy never takes insurance
+func (t *Trainer) GradeInsurance(upcard game.Card, took bool) Grade {
+	chosen := "decline insurance"
+	if took {
+		chosen = "take insurance"
+	}
+
+	grade := Grade{
+		Category: CategoryInsurance,
+		Cell:     fmt.Sprintf("insurance vs %s", UpcardLabel(upcard)),
+		Chosen:   chosen,
+		Correct:  "decline insurance",
+		Reason:   "insurance is a losing side bet without a count: decline",
+		OK:       !took,
+	}
+	t.record(grade)
+	return grade
+}
+
+func (t *Trainer) record(grade Grade) {
+	cat, ok := t.categories[grade.Category]
+	if !ok {
+		cat = &tally{}
+		t.categories[grade.Category] = cat
+	}
+	cat.total++
+
+	cell, ok := t.cells[grade.Cell]
+	if !ok {
+		cell = &cellTally{cell: grade.Cell, correct: grade.Correct}
+		t.cells[grade.Cell] = cell
+	}
+	cell.total++
+
+	if grade.OK {
+		cat.correct++
+	} else {
+		cell.missed++
+	}
+}
+
+// Accuracy returns the number of decisions graded and answered correctly in a category
+func (t *Trainer) Accuracy(category Category) (correct int, total int) {
+	cat, ok := t.categories[category]
+	if !ok {
+		return 0, 0
+	}
+	return cat.correct, cat.total
+}
+
+// MostMissed returns up to n chart cells with the most mistakes, worst first
+func (t *Trainer) MostMissed(n int) []string {
+	missed := make([]*cellTally, 0, len(t.cells))
+	for _, cell := range t.cells {
+		if cell.missed > 0 {
+			missed = append(missed, cell)
+		}
+	}
+
+	sort.Slice(missed, func(i, j int) bool {
+		if missed[i].missed != missed[j].missed {
+			return missed[i].missed > missed[j].missed
+		}
+		return missed[i].cell < missed[j].cell
+	})
+
+	if len(missed) > n {
+		missed = missed[:n]
+	}
+
+	lines := make([]string, 0, len(missed))
+	for _, cell := range missed {
+		lines = append(lines, fmt.Sprintf("%s: missed %d of %d (correct play: %s)", cell.cell, cell.missed, cell.total, cell.correct))
+	}
+	return lines
+}
+
+// Report renders the session's accuracy by category and its most-missed cells
+func (t *Trainer) Report() string {
+	var sb strings.Builder
+
+	sb.WriteString("Trainer Report\n")
+	sb.WriteString("--------------\n")
+
+	totalCorrect, total := 0, 0
+	for _, category := range Categories {
+		correct, n := t.Accuracy(category)
+		totalCorrect += correct
+		total += n
+		if n == 0 {
+			sb.WriteString(fmt.Sprintf("  %-12s  no decisions\n", category.String()+":"))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  %-12s  %d/%d (%.1f%%)\n", category.String()+":", correct, n, percent(correct, n)))
+	}
+
+	if total == 0 {
+		sb.WriteString("\nNo decisions were graded.\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("  %-12s  %d/%d (%.1f%%)\n", "Overall:", totalCorrect, total, percent(totalCorrect, total)))
+
+	missed := t.MostMissed(5)
+	if len(missed) == 0 {
+		sb.WriteString("\nNo mistakes this session. 🎯\n")
+		return sb.String()
+	}
+
+	sb.WriteString("\nMost-missed cells:\n")
+	for _, line := range missed {
+		sb.WriteString("  " + line + "\n")
+	}
+
+	return sb.String()
+}
+
+// trainerState is the JSON form of a Trainer
+type trainerState struct {
+	Categories []categoryState `json:"categories"`
+	Cells      []cellState     `json:"cells"`
+}
+
+type categoryState struct {
+	Category Category `json:"category"`
+	Total    int      `json:"total"`
+	Correct  int      `json:"correct"`
+}
+
+type cellState struct {
+	Cell    string `json:"cell"`
+	Correct string `json:"correct"`
+	Total   int    `json:"total"`
+	Missed  int    `json:"missed"`
+}
+
+// MarshalJSON encodes the session record so it can be saved with the game
+func (t *Trainer) MarshalJSON() ([]byte, error) {
+	state := trainerState{Categories: []categoryState{}, Cells: []cellState{}}
+	for _, category := range Categories {
+		if cat, ok := t.categories[category]; ok {
+			state.Categories = append(state.Categories, categoryState{category, cat.total, cat.correct})
+		}
+	}
+	for _, cell := range t.cells {
+		state.Cells = append(state.Cells, cellState{cell.cell, cell.correct, cell.total, cell.missed})
+	}
+	sort.Slice(state.Cells, func(i, j int) bool { return state.Cells[i].Cell < state.Cells[j].Cell })
+	return json.Marshal(state)
+}
+
+// UnmarshalJSON restores a session record written by MarshalJSON
+func (t *Trainer) UnmarshalJSON(data []byte) error {
+	var state trainerState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	*t = *NewTrainer()
+	for _, cat := range state.Categories {
+		t.categories[cat.Category] = &tally{total: cat.Total, correct: cat.Correct}
+	}
+	for _, cell := range state.Cells {
+		t.cells[cell.Cell] = &cellTally{cell: cell.Cell, correct: cell.Correct, total: cell.Total, missed: cell.Missed}
+	}
+	return nil
+}
+
+func percent(n, total int) float64 {
+	return float64(n) * 100 / float64(total)
+}
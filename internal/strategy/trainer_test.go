@@ -0,0 +1,106 @@
+package strategy
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/DanDo385/blackjack-cli/internal/game"
+)
+
+// TestTrainerAccuracy grades known right and wrong decisions and checks the report
+func TestTrainerAccuracy(t *testing.T) {
+	rules := game.DefaultRules()
+	trainer := NewTrainer()
+
+	decisions := []struct {
+		hand   string
+		upcard string
+		chosen game.Action
+		ok     bool
+	}{
+		{"10S 6C", "7H", game.ActionHit, true},
+		{"10S 2C", "2H", game.ActionStand, false},
+		{"10D 2H", "2C", game.ActionStand, false},
+		{"AS 7C", "9H", game.ActionStand, false},
+		{"AS 7C", "3H", game.ActionDouble, true},
+		{"8S 8C", "10H", game.ActionSplit, true},
+		{"9S 9C", "7H", game.ActionSplit, false},
+		{"10S 6C", "10H", game.ActionSurrender, true},
+		// Hitting where surrender is right counts against surrender, not hard totals
+		{"10S 6C", "10H", game.ActionHit, false},
+	}
+	for _, d := range decisions {
+		hand := deal(t, d.hand)
+		upcard, err := game.ParseCard(d.upcard)
+		if err != nil {
+			t.Fatal(err)
+		}
+		grade := trainer.GradeAction(hand, upcard, rules, offered(hand, rules), d.chosen)
+		if grade.OK != d.ok {
+			t.Errorf("%s vs %s: %s graded %v, expected %v (%s)", d.hand, d.upcard, d.chosen, grade.OK, d.ok, grade)
+		}
+	}
+	ace := game.Card{Rank: game.Ace, Suit: game.Hearts}
+	for _, took := range []bool{true, false, false} {
+		if grade := trainer.GradeInsurance(ace, took); grade.OK == took {
+			t.Errorf("insurance taken %v graded %v", took, grade.OK)
+		}
+	}
+
+	accuracy := map[Category][2]int{
+		CategoryHard:      {1, 3},
+		CategorySoft:      {1, 2},
+		CategoryPair:      {1, 2},
+		CategorySurrender: {1, 2},
+		CategoryInsurance: {2, 3},
+	}
+	for category, want := range accuracy {
+		if correct, total := trainer.Accuracy(category); correct != want[0] || total != want[1] {
+			t.Errorf("%s: %d/%d correct, expected %d/%d", category, correct, total, want[0], want[1])
+		}
+	}
+
+	report := trainer.Report()
+	for _, want := range []string{
+		"Hard totals:  1/3 (33.3%)",
+		"Soft totals:  1/2 (50.0%)",
+		"Pairs:        1/2 (50.0%)",
+		"Surrender:    1/2 (50.0%)",
+		"Insurance:    2/3 (66.7%)",
+		"Overall:      6/12 (50.0%)",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report is missing %q:\n%s", want, report)
+		}
+	}
+
+	missed := trainer.MostMissed(2)
+	want := []string{
+		"hard 12 vs 2: missed 2 of 2 (correct play: hit)",
+		"hard 16 vs 10: missed 1 of 2 (correct play: surrender)",
+	}
+	if strings.Join(missed, "\n") != strings.Join(want, "\n") {
+		t.Errorf("most missed are %q, expected %q", missed, want)
+	}
+
+	// The record survives a save
+	data, err := json.Marshal(trainer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewTrainer()
+	if err := json.Unmarshal(data, restored); err != nil {
+		t.Fatal(err)
+	}
+	if restored.Report() != report {
+		t.Errorf("restored report is\n%s\nexpected\n%s", restored.Report(), report)
+	}
+}
+
+func TestTrainerReportWithoutDecisions(t *testing.T) {
+	report := NewTrainer().Report()
+	if !strings.Contains(report, "No decisions were graded.") {
+		t.Errorf("report is\n%s", report)
+	}
+}
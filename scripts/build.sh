@@ -4,4 +4,6 @@ set -euo pipefail
 
 echo "Building blackjack CLI..."
 go build -o ./bin/blackjack ./cmd/blackjack
-echo "Build complete! Binary at ./bin/blackjack"
+go build -o ./bin/blacksim ./cmd/blacksim
+go build -o ./bin/blackjack-server ./cmd/blackjack-server
+echo "Build complete! Binaries at ./bin/blackjack, ./bin/blacksim and ./bin/blackjack-server"